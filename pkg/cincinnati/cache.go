@@ -0,0 +1,115 @@
+package cincinnati
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGraphCacheTTL is how long update graphs are cached by default.
+const DefaultGraphCacheTTL = 10 * time.Minute
+
+// graphs caches the update graphs fetched by all clients in the process.
+var graphs = &graphCache{ttl: DefaultGraphCacheTTL, entries: map[string]cachedGraph{}}
+
+// ConfigureGraphCache sets how long fetched update graphs are cached and
+// the directory they are persisted to. A zero ttl disables caching and
+// an empty dir keeps cached graphs in memory only.
+func ConfigureGraphCache(ttl time.Duration, dir string) {
+	graphs.mu.Lock()
+	defer graphs.mu.Unlock()
+	graphs.ttl = ttl
+	graphs.dir = dir
+	graphs.entries = map[string]cachedGraph{}
+}
+
+// graphCache caches update graph responses by endpoint, channel, and architecture.
+type graphCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	dir     string
+	entries map[string]cachedGraph
+}
+
+// cachedGraph is an update graph response and when it was fetched.
+type cachedGraph struct {
+	Fetched time.Time       `json:"fetched"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// graphCacheKey returns the cache key of the graph requested from uri.
+// Query parameters that do not select the graph, such as the client
+// identifier and the current version, are not part of the key.
+func graphCacheKey(uri *url.URL) string {
+	query := uri.Query()
+	last := func(key string) string {
+		values := query[key]
+		if len(values) == 0 {
+			return ""
+		}
+		return values[len(values)-1]
+	}
+	endpoint := url.URL{Scheme: uri.Scheme, Host: uri.Host, Path: uri.Path}
+	return fmt.Sprintf("%s?arch=%s&channel=%s", endpoint.String(), last("arch"), last("channel"))
+}
+
+// get returns the cached response body for key, if it has not expired.
+func (c *graphCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	entry, found := c.entries[key]
+	if !found && c.dir != "" {
+		data, err := ioutil.ReadFile(c.path(key))
+		if err == nil && json.Unmarshal(data, &entry) == nil {
+			found = true
+			c.entries[key] = entry
+		}
+	}
+	if !found || time.Since(entry.Fetched) > c.ttl {
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// put caches the response body for key.
+func (c *graphCache) put(key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return
+	}
+	entry := cachedGraph{Fetched: time.Now(), Body: body}
+	c.entries[key] = entry
+	if c.dir == "" {
+		return
+	}
+	// Failing to persist the cache only costs a later request.
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logrus.Debugf("error encoding cached graph for %s: %v", key, err)
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0750); err != nil {
+		logrus.Debugf("error creating graph cache directory: %v", err)
+		return
+	}
+	if err := ioutil.WriteFile(c.path(key), data, 0600); err != nil {
+		logrus.Debugf("error persisting cached graph for %s: %v", key, err)
+	}
+}
+
+// path returns the file the graph for key is persisted to.
+func (c *graphCache) path(key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
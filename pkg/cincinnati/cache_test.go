@@ -0,0 +1,106 @@
+package cincinnati
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphCache(t *testing.T) {
+	type spec struct {
+		name string
+		ttl  time.Duration
+		// persist configures the cache with a directory.
+		persist bool
+		// reset clears the in-memory cache between requests.
+		reset bool
+		// wait is the time to wait between requests.
+		wait     time.Duration
+		channels []string
+		exp      int
+	}
+
+	cases := []spec{
+		{
+			name:     "Valid/Hit",
+			ttl:      time.Minute,
+			channels: []string{"stable-4.0", "stable-4.0"},
+			exp:      1,
+		},
+		{
+			name:     "Valid/KeyedByChannel",
+			ttl:      time.Minute,
+			channels: []string{"stable-4.0", "fast-4.0", "stable-4.0"},
+			exp:      2,
+		},
+		{
+			name:     "Valid/Expired",
+			ttl:      time.Millisecond,
+			wait:     10 * time.Millisecond,
+			channels: []string{"stable-4.0", "stable-4.0"},
+			exp:      2,
+		},
+		{
+			name:     "Valid/Disabled",
+			channels: []string{"stable-4.0", "stable-4.0"},
+			exp:      2,
+		},
+		{
+			name:     "Valid/Persisted",
+			ttl:      time.Minute,
+			persist:  true,
+			reset:    true,
+			channels: []string{"stable-4.0", "stable-4.0"},
+			exp:      1,
+		},
+		{
+			name:     "Valid/NotPersisted",
+			ttl:      time.Minute,
+			reset:    true,
+			channels: []string{"stable-4.0", "stable-4.0"},
+			exp:      2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var dir string
+			if c.persist {
+				dir = t.TempDir()
+			}
+			ConfigureGraphCache(c.ttl, dir)
+			t.Cleanup(func() { ConfigureGraphCache(DefaultGraphCacheTTL, "") })
+
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if _, err := w.Write([]byte(`{"nodes": [{"version": "4.0.0", "payload": "quay.io/openshift-release-dev/ocp-release:4.0.0"}], "edges": []}`)); err != nil {
+					t.Fatal(err)
+				}
+			}))
+			t.Cleanup(ts.Close)
+			endpoint, err := url.Parse(ts.URL)
+			require.NoError(t, err)
+
+			for _, channel := range c.channels {
+				if c.reset {
+					ConfigureGraphCache(c.ttl, dir)
+				}
+				time.Sleep(c.wait)
+				u := *endpoint
+				client := &mockClient{url: &u}
+				client.SetQueryParams("test-arch", channel, "")
+				graph, err := getGraphData(context.Background(), client)
+				require.NoError(t, err)
+				require.Len(t, graph.Nodes, 1)
+				require.Equal(t, "4.0.0", graph.Nodes[0].Version.String())
+			}
+			require.Equal(t, c.exp, requests)
+		})
+	}
+}
@@ -59,8 +59,9 @@ type Update node
 // the current version within that graph (typically the root node), and then
 // finding all of the children. These children are the available updates for
 // the current version and their payloads indicate from where the actual update
-// image can be downloaded.
-func GetUpdates(ctx context.Context, c Client, arch string, channel string, version semver.Version, reqVer semver.Version) (Update, Update, []Update, error) {
+// image can be downloaded. Conditional update edges are followed if their risks
+// are accepted, and updates reached by them are returned with those risks.
+func GetUpdates(ctx context.Context, c Client, arch string, channel string, version semver.Version, reqVer semver.Version, accept AcceptRisks) (Update, Update, []Update, error) {
 	var current Update
 	var requested Update
 	// Prepare parametrized cincinnati query.
@@ -106,18 +107,7 @@ func GetUpdates(ctx context.Context, c Client, arch string, channel string, vers
 		}
 	}
 
-	edgesByOrigin := make(map[int][]int, len(graph.Nodes))
-	for _, edge := range graph.Edges {
-		edgesByOrigin[edge.Origin] = append(edgesByOrigin[edge.Origin], edge.Destination)
-	}
-
-	// Sort destination by semver to ensure deterministic result
-	for origin, destinations := range edgesByOrigin {
-		sort.Slice(destinations, func(i, j int) bool {
-			return graph.Nodes[destinations[i]].Version.GT(graph.Nodes[destinations[j]].Version)
-		})
-		edgesByOrigin[origin] = destinations
-	}
+	edgesByOrigin, risks := graph.adjacency(accept)
 
 	var shortestPath func(map[int][]int, int, int, path) []int
 	shortestPath = func(g map[int][]int, start, end int, path path) []int {
@@ -147,19 +137,16 @@ func GetUpdates(ctx context.Context, c Client, arch string, channel string, vers
 
 	nextIdxs := shortestPath(edgesByOrigin, currentIdx, destinationIdx, path{})
 
-	var updates []Update
-	for _, i := range nextIdxs {
-		updates = append(updates, Update(graph.Nodes[i]))
-	}
+	updates := graph.pathUpdates(nextIdxs, risks)
 
 	return current, requested, updates, nil
 }
 
 // CalculateUpgrades fetches and calculates all the update payloads from the specified
 // upstream Cincinnati stack given the current and target version and channel
-func CalculateUpgrades(ctx context.Context, c Client, arch, sourceChannel, targetChannel string, startVer, reqVer semver.Version) (Update, Update, []Update, error) {
+func CalculateUpgrades(ctx context.Context, c Client, arch, sourceChannel, targetChannel string, startVer, reqVer semver.Version, accept AcceptRisks) (Update, Update, []Update, error) {
 	if sourceChannel == targetChannel {
-		return GetUpdates(ctx, c, arch, targetChannel, startVer, reqVer)
+		return GetUpdates(ctx, c, arch, targetChannel, startVer, reqVer, accept)
 	}
 
 	// Perform initial calculation for the source channel and
@@ -169,12 +156,12 @@ func CalculateUpgrades(ctx context.Context, c Client, arch, sourceChannel, targe
 	if err != nil {
 		return Update{}, Update{}, nil, fmt.Errorf("cannot get latest: %v", err)
 	}
-	current, _, upgrades, err := GetUpdates(ctx, c, arch, sourceChannel, startVer, latest)
+	current, _, upgrades, err := GetUpdates(ctx, c, arch, sourceChannel, startVer, latest, accept)
 	if err != nil {
 		return Update{}, Update{}, nil, fmt.Errorf("cannot get current: %v", err)
 	}
 
-	requested, newUpgrades, err := calculate(ctx, c, arch, sourceChannel, targetChannel, latest, reqVer)
+	requested, newUpgrades, err := calculate(ctx, c, arch, sourceChannel, targetChannel, latest, reqVer, accept)
 	upgrades = append(upgrades, newUpgrades...)
 
 	var finalUpgrades []Update
@@ -189,7 +176,7 @@ func CalculateUpgrades(ctx context.Context, c Client, arch, sourceChannel, targe
 	return current, requested, finalUpgrades, err
 }
 
-func calculate(ctx context.Context, c Client, arch, sourceChannel, targetChannel string, startVer, reqVer semver.Version) (requested Update, upgrades []Update, err error) {
+func calculate(ctx context.Context, c Client, arch, sourceChannel, targetChannel string, startVer, reqVer semver.Version, accept AcceptRisks) (requested Update, upgrades []Update, err error) {
 	// Get semver representation of source and target channel versions
 	sourceIdx := strings.LastIndex(sourceChannel, "-")
 	if sourceIdx == -1 {
@@ -233,13 +220,13 @@ func calculate(ctx context.Context, c Client, arch, sourceChannel, targetChannel
 	if _, found := foundVersions[startVer.String()]; !found {
 		// If blocked path is found, just return the requested version and any accumulated
 		// upgrades to the caller
-		_, requested, _, err = GetUpdates(ctx, c, arch, targetChannel, targetVer, targetVer)
+		_, requested, _, err = GetUpdates(ctx, c, arch, targetChannel, targetVer, targetVer, accept)
 		logrus.Warnf("No upgrade path for %s in target channel %s", startVer.String(), targetChannel)
 		return requested, upgrades, err
 	}
 
 	logrus.Debugf("Getting updates for version %s in channel %s", startVer.String(), currChannel)
-	_, requested, upgrades, err = GetUpdates(ctx, c, arch, currChannel, startVer, targetVer)
+	_, requested, upgrades, err = GetUpdates(ctx, c, arch, currChannel, startVer, targetVer, accept)
 	if err != nil {
 		return requested, upgrades, nil
 	}
@@ -248,7 +235,7 @@ func calculate(ctx context.Context, c Client, arch, sourceChannel, targetChannel
 		return requested, upgrades, nil
 	}
 
-	req, up, err := calculate(ctx, c, arch, currChannel, targetChannel, targetVer, reqVer)
+	req, up, err := calculate(ctx, c, arch, currChannel, targetChannel, targetVer, reqVer, accept)
 	if err != nil {
 		return requested, upgrades, nil
 	}
@@ -293,6 +280,35 @@ func GetChannelMinOrMax(ctx context.Context, c Client, arch string, channel stri
 	return Vers[len(Vers)-1], nil
 }
 
+// GetReleases fetches all releases from the specified upstream Cincinnati
+// stack given architecture and channel, sorted by version
+func GetReleases(ctx context.Context, c Client, arch string, channel string) ([]Update, error) {
+	// Prepare parametrized cincinnati query.
+	c.SetQueryParams(arch, channel, "")
+
+	graph, err := getGraphData(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("error getting graph data for channel %s", channel)
+	}
+
+	if len(graph.Nodes) == 0 {
+		return nil, &Error{
+			Reason:  "NoVersionsFound",
+			Message: fmt.Sprintf("no cluster versions found for %q in the %q channel", arch, channel),
+		}
+	}
+
+	releases := make([]Update, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		releases = append(releases, Update(node))
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Version.LT(releases[j].Version)
+	})
+
+	return releases, nil
+}
+
 // GetChannels fetches the channels containing update payloads from the specified
 // upstream Cincinnati stack
 func GetChannels(ctx context.Context, c Client, channel string) (map[string]struct{}, error) {
@@ -351,6 +367,15 @@ func GetVersions(ctx context.Context, c Client, channel string) ([]semver.Versio
 func getGraphData(ctx context.Context, c Client) (graph graph, err error) {
 	transport := c.GetTransport()
 	uri := c.GetURL()
+	key := graphCacheKey(uri)
+	if body, found := graphs.get(key); found {
+		klog.V(5).Infof("Using cached update graph for %s", key)
+		if err = json.Unmarshal(body, &graph); err != nil {
+			return graph, &Error{Reason: "ResponseInvalid", Message: err.Error(), cause: err}
+		}
+		return graph, nil
+	}
+
 	// Download the update graph.
 	req, err := http.NewRequest("GET", uri.String(), nil)
 	if err != nil {
@@ -397,19 +422,67 @@ func getGraphData(ctx context.Context, c Client) (graph graph, err error) {
 	if err = json.Unmarshal(body, &graph); err != nil {
 		return graph, &Error{Reason: "ResponseInvalid", Message: err.Error(), cause: err}
 	}
+	graphs.put(key, body)
 
 	return graph, nil
 }
 
 type graph struct {
-	Nodes []node
-	Edges []edge
+	Nodes            []node
+	Edges            []edge
+	ConditionalEdges []conditionalEdges `json:"conditionalEdges,omitempty"`
+}
+
+// adjacency returns the destinations of the edges from each node, sorted by
+// version in descending order, including the conditional edges whose risks are
+// accepted. The risks of accepted conditional edges that are not also
+// unconditional edges are returned by edge.
+func (g graph) adjacency(accept AcceptRisks) (map[int][]int, map[edge][]ConditionalUpdateRisk) {
+	edgesByOrigin := make(map[int][]int, len(g.Nodes))
+	unconditional := make(map[edge]struct{}, len(g.Edges))
+	for _, edge := range g.Edges {
+		edgesByOrigin[edge.Origin] = append(edgesByOrigin[edge.Origin], edge.Destination)
+		unconditional[edge] = struct{}{}
+	}
+	risks := make(map[edge][]ConditionalUpdateRisk)
+	for edge, edgeRisks := range acceptedConditionalEdges(g, accept) {
+		if _, found := unconditional[edge]; !found {
+			edgesByOrigin[edge.Origin] = append(edgesByOrigin[edge.Origin], edge.Destination)
+			risks[edge] = edgeRisks
+		}
+	}
+
+	// Sort destination by semver to ensure deterministic result
+	for origin, destinations := range edgesByOrigin {
+		sort.Slice(destinations, func(i, j int) bool {
+			return g.Nodes[destinations[i]].Version.GT(g.Nodes[destinations[j]].Version)
+		})
+		edgesByOrigin[origin] = destinations
+	}
+	return edgesByOrigin, risks
+}
+
+// pathUpdates returns the updates for the nodes on path with
+// the risks of the conditional edges they are reached by.
+func (g graph) pathUpdates(path []int, risks map[edge][]ConditionalUpdateRisk) []Update {
+	var updates []Update
+	for j, i := range path {
+		update := Update(g.Nodes[i])
+		if j > 0 {
+			update.Risks = risks[edge{Origin: path[j-1], Destination: i}]
+		}
+		updates = append(updates, update)
+	}
+	return updates
 }
 
 type node struct {
 	Version  semver.Version    `json:"version"`
 	Image    string            `json:"payload"`
 	Metadata map[string]string `json:"metadata,omitempty"`
+	// Risks of the conditional update edge this node was
+	// reached by on a calculated upgrade path.
+	Risks []ConditionalUpdateRisk `json:"-"`
 }
 
 type edge struct {
@@ -88,7 +88,7 @@ func TestGetUpdates(t *testing.T) {
 			require.NoError(t, err)
 			c := &mockClient{url: endpoint}
 
-			current, requested, updates, err := GetUpdates(context.Background(), c, arch, channelName, semver.MustParse(test.version), semver.MustParse(test.reqVer))
+			current, requested, updates, err := GetUpdates(context.Background(), c, arch, channelName, semver.MustParse(test.version), semver.MustParse(test.reqVer), nil)
 			if test.err == "" {
 				require.NoError(t, err)
 				require.Equal(t, test.current, current)
@@ -306,7 +306,7 @@ func TestCalculateUpgrades(t *testing.T) {
 			endpoint, err := url.Parse(ts.URL)
 			require.NoError(t, err)
 
-			cur, req, updates, err := CalculateUpgrades(context.Background(), &mockClient{url: endpoint}, arch, test.sourceChannel, test.targetChannel, test.last, test.req)
+			cur, req, updates, err := CalculateUpgrades(context.Background(), &mockClient{url: endpoint}, arch, test.sourceChannel, test.targetChannel, test.last, test.req, nil)
 
 			if test.err == "" {
 				require.NoError(t, err)
@@ -57,15 +57,19 @@ func (c *ocpClient) GetID() uuid.UUID {
 
 func (c *ocpClient) SetQueryParams(arch, channel, version string) {
 	queryParams := c.url.Query()
-	queryParams.Add("id", c.id.String())
+	queryParams.Set("id", c.id.String())
 	params := map[string]string{
 		"arch":    arch,
 		"channel": channel,
 		"version": version,
 	}
+	// Replace the parameters of previous queries so a
+	// client can be reused to query other channels.
 	for key, value := range params {
 		if value != "" {
-			queryParams.Add(key, value)
+			queryParams.Set(key, value)
+		} else {
+			queryParams.Del(key)
 		}
 	}
 	c.url.RawQuery = queryParams.Encode()
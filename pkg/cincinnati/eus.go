@@ -0,0 +1,169 @@
+package cincinnati
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// EUSChannelPrefix is the prefix of Extended Update Support channels.
+const EUSChannelPrefix = "eus"
+
+// CalculateEUSUpgrades fetches and calculates the shortest upgrade path from startVer
+// in sourceChannel to reqVer in targetChannel, such as an EUS-to-EUS upgrade that
+// traverses an odd minor version only as an intermediate hop. The releases of the
+// minor versions between the channels are taken from the target channel and from
+// the channels named with intermediatePrefix. Releases in preferred are chosen over
+// other releases on upgrade paths of the same length.
+func CalculateEUSUpgrades(ctx context.Context, c Client, arch, sourceChannel, targetChannel, intermediatePrefix string, startVer, reqVer semver.Version, preferred []string, accept AcceptRisks) (Update, Update, []Update, error) {
+	_, source, err := parseChannel(sourceChannel)
+	if err != nil {
+		return Update{}, Update{}, nil, err
+	}
+	_, target, err := parseChannel(targetChannel)
+	if err != nil {
+		return Update{}, Update{}, nil, err
+	}
+
+	channels := []string{sourceChannel, targetChannel}
+	// EUS channels contain the releases of the intermediate minor versions,
+	// so intermediate channels are only needed for other channel prefixes.
+	if intermediatePrefix != EUSChannelPrefix {
+		for minor := source.Minor + 1; minor < target.Minor; minor++ {
+			channels = append(channels, fmt.Sprintf("%s-%d.%d", intermediatePrefix, source.Major, minor))
+		}
+	}
+	var graphs []graph
+	for _, channel := range channels {
+		c.SetQueryParams(arch, channel, "")
+		g, err := getGraphData(ctx, c)
+		if err != nil {
+			return Update{}, Update{}, nil, fmt.Errorf("error getting graph data for channel %s", channel)
+		}
+		graphs = append(graphs, g)
+	}
+	merged := mergeGraphs(graphs...)
+
+	currentIdx, destinationIdx := -1, -1
+	for i, node := range merged.Nodes {
+		if startVer.EQ(node.Version) {
+			currentIdx = i
+		}
+		if reqVer.EQ(node.Version) {
+			destinationIdx = i
+		}
+	}
+	if currentIdx == -1 {
+		return Update{}, Update{}, nil, &Error{
+			Reason:  "VersionNotFound",
+			Message: fmt.Sprintf("current version %s not found in the %q channel", startVer, sourceChannel),
+		}
+	}
+	if destinationIdx == -1 {
+		return Update{}, Update{}, nil, &Error{
+			Reason:  "VersionNotFound",
+			Message: fmt.Sprintf("requested version %s not found in the %q channel", reqVer, targetChannel),
+		}
+	}
+
+	isPreferred := make(map[string]struct{}, len(preferred))
+	for _, version := range preferred {
+		isPreferred[version] = struct{}{}
+	}
+	edgesByOrigin, risks := merged.adjacency(accept)
+	path := preferredPath(edgesByOrigin, currentIdx, destinationIdx, func(i int) bool {
+		_, found := isPreferred[merged.Nodes[i].Version.String()]
+		return found
+	})
+
+	current, requested := Update(merged.Nodes[currentIdx]), Update(merged.Nodes[destinationIdx])
+	return current, requested, merged.pathUpdates(path, risks), nil
+}
+
+// parseChannel splits a channel name into its prefix and version.
+func parseChannel(channel string) (string, semver.Version, error) {
+	idx := strings.LastIndex(channel, "-")
+	if idx == -1 {
+		return "", semver.Version{}, fmt.Errorf("invalid channel name %s", channel)
+	}
+	version, err := semver.Parse(fmt.Sprintf("%s.0", channel[idx+1:]))
+	if err != nil {
+		return "", semver.Version{}, fmt.Errorf("invalid channel name %s: %v", channel, err)
+	}
+	return channel[:idx], version, nil
+}
+
+// mergeGraphs merges graphs into one graph with a node for each version.
+func mergeGraphs(graphs ...graph) graph {
+	var merged graph
+	idxByVersion := make(map[string]int)
+	seenEdges := make(map[edge]struct{})
+	for _, g := range graphs {
+		idxs := make([]int, len(g.Nodes))
+		for i, node := range g.Nodes {
+			idx, found := idxByVersion[node.Version.String()]
+			if !found {
+				idx = len(merged.Nodes)
+				idxByVersion[node.Version.String()] = idx
+				merged.Nodes = append(merged.Nodes, node)
+			}
+			idxs[i] = idx
+		}
+		for _, e := range g.Edges {
+			mergedEdge := edge{Origin: idxs[e.Origin], Destination: idxs[e.Destination]}
+			if _, found := seenEdges[mergedEdge]; !found {
+				seenEdges[mergedEdge] = struct{}{}
+				merged.Edges = append(merged.Edges, mergedEdge)
+			}
+		}
+		merged.ConditionalEdges = append(merged.ConditionalEdges, g.ConditionalEdges...)
+	}
+	return merged
+}
+
+// preferredPath returns the shortest path from start to end in the graph of
+// edgesByOrigin. Of the shortest paths, the path with the most preferred
+// nodes is returned. An empty path is returned if end is not reachable.
+func preferredPath(edgesByOrigin map[int][]int, start, end int, preferred func(int) bool) []int {
+	penalty := func(i int) int {
+		if preferred(i) {
+			return 0
+		}
+		return 1
+	}
+	dist := map[int]int{start: 0}
+	cost := map[int]int{start: penalty(start)}
+	prev := map[int]int{}
+	for frontier := []int{start}; len(frontier) != 0; {
+		var next []int
+		for _, origin := range frontier {
+			for _, destination := range edgesByOrigin[origin] {
+				c := cost[origin] + penalty(destination)
+				d, found := dist[destination]
+				switch {
+				case !found:
+					dist[destination] = dist[origin] + 1
+					cost[destination] = c
+					prev[destination] = origin
+					next = append(next, destination)
+				case d == dist[origin]+1 && c < cost[destination]:
+					cost[destination] = c
+					prev[destination] = origin
+				}
+			}
+		}
+		frontier = next
+	}
+
+	if _, found := dist[end]; !found {
+		return []int{}
+	}
+	path := []int{end}
+	for i := end; i != start; {
+		i = prev[i]
+		path = append([]int{i}, path...)
+	}
+	return path
+}
@@ -0,0 +1,145 @@
+package cincinnati
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateEUSUpgrades(t *testing.T) {
+	type spec struct {
+		name      string
+		prefix    string
+		preferred []string
+		accept    AcceptRisks
+		exp       []string
+		expRisks  map[string][]string
+		err       string
+	}
+
+	cases := []spec{
+		{
+			name:   "Valid/EUSPrefix",
+			prefix: "eus",
+			exp:    []string{"4.10.2", "4.11.2", "4.12.1"},
+		},
+		{
+			name:      "Valid/PreferredPath",
+			prefix:    "eus",
+			preferred: []string{"4.10.2", "4.11.1", "4.12.1"},
+			exp:       []string{"4.10.2", "4.11.1", "4.12.1"},
+		},
+		{
+			name:   "Valid/StablePrefix",
+			prefix: "stable",
+			exp:    []string{"4.10.2", "4.11.3", "4.12.1"},
+		},
+		{
+			name:      "Valid/OverlappingConditionalEdge",
+			prefix:    "eus",
+			preferred: []string{"4.11.4"},
+			accept:    func([]ConditionalUpdateRisk) bool { return true },
+			exp:       []string{"4.10.2", "4.11.4", "4.12.1"},
+			expRisks:  map[string][]string{"4.11.4": {"TestRisk"}},
+		},
+		{
+			name:   "Invalid/UnknownIntermediateChannel",
+			prefix: "fast",
+			err:    "error getting graph data for channel fast-4.11",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(eusHandler(t)))
+			t.Cleanup(ts.Close)
+			endpoint, err := url.Parse(ts.URL)
+			require.NoError(t, err)
+
+			current, requested, updates, err := CalculateEUSUpgrades(context.Background(), &mockClient{url: endpoint}, "test-arch",
+				"eus-4.10", "eus-4.12", c.prefix, semver.MustParse("4.10.2"), semver.MustParse("4.12.1"), c.preferred, c.accept)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "4.10.2", current.Version.String())
+			require.Equal(t, "4.12.1", requested.Version.String())
+			var versions []string
+			risks := map[string][]string{}
+			for _, update := range updates {
+				versions = append(versions, update.Version.String())
+				for _, risk := range update.Risks {
+					risks[update.Version.String()] = append(risks[update.Version.String()], risk.Name)
+				}
+			}
+			require.Equal(t, c.exp, versions)
+			if c.expRisks == nil {
+				c.expRisks = map[string][]string{}
+			}
+			require.Equal(t, c.expRisks, risks)
+		})
+	}
+}
+
+func eusHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		channels := r.URL.Query()["channel"]
+		var body string
+		switch channels[len(channels)-1] {
+		case "eus-4.10":
+			body = `{
+				"nodes": [
+				  {"version": "4.10.1", "payload": "quay.io/openshift-release-dev/ocp-release:4.10.1"},
+				  {"version": "4.10.2", "payload": "quay.io/openshift-release-dev/ocp-release:4.10.2"},
+				  {"version": "4.11.4", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.4"}
+				],
+				"edges": [[0,1]],
+				"conditionalEdges": [
+				  {
+					"edges": [{"from": "4.10.2", "to": "4.11.4"}],
+					"risks": [{"url": "https://access.redhat.com/solutions/0000000", "name": "TestRisk", "message": "Clusters using the test feature may fail to update.", "matchingRules": [{"type": "Always"}]}]
+				  }
+				]
+			  }`
+		case "eus-4.12":
+			body = `{
+				"nodes": [
+				  {"version": "4.10.2", "payload": "quay.io/openshift-release-dev/ocp-release:4.10.2"},
+				  {"version": "4.11.1", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.1"},
+				  {"version": "4.11.2", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.2"},
+				  {"version": "4.12.1", "payload": "quay.io/openshift-release-dev/ocp-release:4.12.1"},
+				  {"version": "4.11.3", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.3"},
+				  {"version": "4.11.4", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.4"}
+				],
+				"edges": [[0,1],[0,2],[1,3],[2,3],[4,3],[5,3]],
+				"conditionalEdges": [
+				  {
+					"edges": [{"from": "4.10.2", "to": "4.11.4"}],
+					"risks": [{"url": "https://access.redhat.com/solutions/0000000", "name": "TestRisk", "message": "Clusters using the test feature may fail to update.", "matchingRules": [{"type": "Always"}]}]
+				  }
+				]
+			  }`
+		case "stable-4.11":
+			body = `{
+				"nodes": [
+				  {"version": "4.10.2", "payload": "quay.io/openshift-release-dev/ocp-release:4.10.2"},
+				  {"version": "4.11.3", "payload": "quay.io/openshift-release-dev/ocp-release:4.11.3"}
+				],
+				"edges": [[0,1]]
+			  }`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cincinnati
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+)
+
+// ConditionalUpdateRisk is a risk that applies to
+// a set of conditional update edges in the graph.
+type ConditionalUpdateRisk struct {
+	// URL links to documentation about the risk.
+	URL string `json:"url"`
+	// Name is the CamelCase reason for the risk.
+	Name string `json:"name"`
+	// Message is a human-readable description of the risk.
+	Message string `json:"message"`
+	// MatchingRules determine whether a cluster is exposed to the risk.
+	MatchingRules []ClusterCondition `json:"matchingRules"`
+}
+
+// ClusterCondition is a rule matching clusters exposed to a risk.
+type ClusterCondition struct {
+	Type   string       `json:"type"`
+	PromQL *PromQLQuery `json:"promql,omitempty"`
+}
+
+// PromQLQuery is a PromQL query matching clusters exposed to a risk.
+type PromQLQuery struct {
+	PromQL string `json:"promql"`
+}
+
+// ConditionalUpdate is an update that is only recommended
+// for clusters not exposed to its risks.
+type ConditionalUpdate struct {
+	From  semver.Version
+	To    semver.Version
+	Risks []ConditionalUpdateRisk
+}
+
+// AcceptRisks reports whether a conditional update edge with
+// the given risks is followed when calculating upgrades.
+// A nil AcceptRisks follows no conditional update edges.
+type AcceptRisks func(risks []ConditionalUpdateRisk) bool
+
+// RiskPolicy returns the AcceptRisks function for the
+// conditional update policy of a release channel.
+func RiskPolicy(ch v1alpha2.ReleaseChannel) AcceptRisks {
+	switch ch.ConditionalUpdates {
+	case v1alpha2.ConditionalUpdatesIncludeAll:
+		return func([]ConditionalUpdateRisk) bool {
+			return true
+		}
+	case v1alpha2.ConditionalUpdatesAcceptListed:
+		accepted := make(map[string]struct{}, len(ch.AcceptedRisks))
+		for _, name := range ch.AcceptedRisks {
+			accepted[name] = struct{}{}
+		}
+		return func(risks []ConditionalUpdateRisk) bool {
+			for _, risk := range risks {
+				if _, found := accepted[risk.Name]; !found {
+					return false
+				}
+			}
+			return true
+		}
+	default:
+		return nil
+	}
+}
+
+// GetConditionalUpdates fetches the conditional updates from the specified
+// upstream Cincinnati stack given architecture and channel, sorted by version
+func GetConditionalUpdates(ctx context.Context, c Client, arch string, channel string) ([]ConditionalUpdate, error) {
+	// Prepare parametrized cincinnati query.
+	c.SetQueryParams(arch, channel, "")
+
+	graph, err := getGraphData(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("error getting graph data for channel %s", channel)
+	}
+
+	var updates []ConditionalUpdate
+	for _, conditional := range graph.ConditionalEdges {
+		for _, edge := range conditional.Edges {
+			from, err := semver.Parse(edge.From)
+			if err != nil {
+				return nil, err
+			}
+			to, err := semver.Parse(edge.To)
+			if err != nil {
+				return nil, err
+			}
+			updates = append(updates, ConditionalUpdate{From: from, To: to, Risks: conditional.Risks})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		if !updates[i].From.EQ(updates[j].From) {
+			return updates[i].From.LT(updates[j].From)
+		}
+		return updates[i].To.LT(updates[j].To)
+	})
+
+	return updates, nil
+}
+
+type conditionalEdges struct {
+	Edges []conditionalEdge       `json:"edges"`
+	Risks []ConditionalUpdateRisk `json:"risks"`
+}
+
+type conditionalEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// acceptedConditionalEdges returns the conditional edges between nodes
+// in graph whose risks are accepted, keyed by origin and destination.
+// Each risk is returned once per edge, since merged graphs of overlapping
+// channels contain the same conditional edges more than once.
+func acceptedConditionalEdges(graph graph, accept AcceptRisks) map[edge][]ConditionalUpdateRisk {
+	accepted := make(map[edge][]ConditionalUpdateRisk)
+	if accept == nil {
+		return accepted
+	}
+	idxByVersion := make(map[string]int, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		idxByVersion[node.Version.String()] = i
+	}
+	for _, conditional := range graph.ConditionalEdges {
+		if !accept(conditional.Risks) {
+			continue
+		}
+		for _, ce := range conditional.Edges {
+			origin, found := idxByVersion[ce.From]
+			if !found {
+				continue
+			}
+			destination, found := idxByVersion[ce.To]
+			if !found {
+				continue
+			}
+			e := edge{Origin: origin, Destination: destination}
+			for _, risk := range conditional.Risks {
+				if !hasRisk(accepted[e], risk.Name) {
+					accepted[e] = append(accepted[e], risk)
+				}
+			}
+		}
+	}
+	return accepted
+}
+
+// hasRisk returns true if risks contains a risk named name.
+func hasRisk(risks []ConditionalUpdateRisk, name string) bool {
+	for _, risk := range risks {
+		if risk.Name == name {
+			return true
+		}
+	}
+	return false
+}
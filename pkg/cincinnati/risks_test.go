@@ -0,0 +1,132 @@
+package cincinnati
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+)
+
+var testRisk = ConditionalUpdateRisk{
+	URL:     "https://access.redhat.com/solutions/0000000",
+	Name:    "TestRisk",
+	Message: "Clusters using the test feature may fail to update.",
+	MatchingRules: []ClusterCondition{
+		{Type: "PromQL", PromQL: &PromQLQuery{PromQL: `cluster_feature_set{name="test"}`}},
+	},
+}
+
+func TestGetUpdatesConditional(t *testing.T) {
+	type spec struct {
+		name    string
+		channel v1alpha2.ReleaseChannel
+		exp     []Update
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/ExcludeRisky",
+			exp:  nil,
+		},
+		{
+			name:    "Valid/IncludeAll",
+			channel: v1alpha2.ReleaseChannel{ConditionalUpdates: v1alpha2.ConditionalUpdatesIncludeAll},
+			exp: []Update{
+				{Version: semver.MustParse("4.9.0"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.0"},
+				{Version: semver.MustParse("4.9.2"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.2", Risks: []ConditionalUpdateRisk{testRisk}},
+			},
+		},
+		{
+			name: "Valid/AcceptListed",
+			channel: v1alpha2.ReleaseChannel{
+				ConditionalUpdates: v1alpha2.ConditionalUpdatesAcceptListed,
+				AcceptedRisks:      []string{"TestRisk"},
+			},
+			exp: []Update{
+				{Version: semver.MustParse("4.9.0"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.0"},
+				{Version: semver.MustParse("4.9.2"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.2", Risks: []ConditionalUpdateRisk{testRisk}},
+			},
+		},
+		{
+			name: "Valid/AcceptListedNotAccepted",
+			channel: v1alpha2.ReleaseChannel{
+				ConditionalUpdates: v1alpha2.ConditionalUpdatesAcceptListed,
+				AcceptedRisks:      []string{"OtherRisk"},
+			},
+			exp: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(conditionalHandler(t)))
+			t.Cleanup(ts.Close)
+			endpoint, err := url.Parse(ts.URL)
+			require.NoError(t, err)
+
+			_, _, updates, err := GetUpdates(context.Background(), &mockClient{url: endpoint}, "test-arch", "stable-4.9",
+				semver.MustParse("4.9.0"), semver.MustParse("4.9.2"), RiskPolicy(c.channel))
+			require.NoError(t, err)
+			require.Equal(t, c.exp, updates)
+		})
+	}
+}
+
+func TestGetConditionalUpdates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(conditionalHandler(t)))
+	t.Cleanup(ts.Close)
+	endpoint, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	updates, err := GetConditionalUpdates(context.Background(), &mockClient{url: endpoint}, "test-arch", "stable-4.9")
+	require.NoError(t, err)
+	require.Equal(t, []ConditionalUpdate{
+		{From: semver.MustParse("4.9.0"), To: semver.MustParse("4.9.2"), Risks: []ConditionalUpdateRisk{testRisk}},
+		{From: semver.MustParse("4.9.1"), To: semver.MustParse("4.9.2"), Risks: []ConditionalUpdateRisk{testRisk}},
+	}, updates)
+}
+
+func conditionalHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{
+			"nodes": [
+			  {
+				"version": "4.9.0",
+				"payload": "quay.io/openshift-release-dev/ocp-release:4.9.0"
+			  },
+			  {
+				"version": "4.9.1",
+				"payload": "quay.io/openshift-release-dev/ocp-release:4.9.1"
+			  },
+			  {
+				"version": "4.9.2",
+				"payload": "quay.io/openshift-release-dev/ocp-release:4.9.2"
+			  }
+			],
+			"edges": [[0,1]],
+			"conditionalEdges": [
+			  {
+				"edges": [{"from": "4.9.1", "to": "4.9.2"}, {"from": "4.9.0", "to": "4.9.2"}],
+				"risks": [
+				  {
+					"url": "https://access.redhat.com/solutions/0000000",
+					"name": "TestRisk",
+					"message": "Clusters using the test feature may fail to update.",
+					"matchingRules": [{"type": "PromQL", "promql": {"promql": "cluster_feature_set{name=\"test\"}"}}]
+				  }
+				]
+			  }
+			]
+		  }`))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Fatal(err)
+		}
+	}
+}
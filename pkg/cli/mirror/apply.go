@@ -0,0 +1,211 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// applyFieldManager is the field manager used for server-side apply.
+const applyFieldManager = "oc-mirror"
+
+// applyKinds are the kinds of generated resources applied with --apply.
+// MachineConfigs are not applied since they cause nodes to reboot.
+var applyKinds = map[string]struct{}{
+	icspKind:        {},
+	idmsKind:        {},
+	itmsKind:        {},
+	"CatalogSource": {},
+	"ConfigMap":     {},
+}
+
+// appliedResource is a resource applied to the cluster and its reported status.
+type appliedResource struct {
+	kind      string
+	namespace string
+	name      string
+	status    string
+}
+
+// applyResults server-side applies the generated resources in dir to the cluster
+// configured by the kubeconfig flags and prints their status. Manifests for all
+// published images are preferred so mirrors from earlier sequences are kept.
+func (o *MirrorOptions) applyResults(ctx context.Context, f kcmdutil.Factory, dir string) error {
+	completeDir := filepath.Join(dir, completeResultsDir)
+	if _, err := os.Stat(completeDir); err == nil {
+		dir = completeDir
+	}
+
+	objs, err := readApplyManifests(dir)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		logrus.Infof("No resources in %s to apply", dir)
+		return nil
+	}
+
+	client, err := f.DynamicClient()
+	if err != nil {
+		return fmt.Errorf("error creating cluster client: %v", err)
+	}
+	mapper, err := f.ToRESTMapper()
+	if err != nil {
+		return fmt.Errorf("error discovering cluster resources: %v", err)
+	}
+	namespace, _, err := f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Applying %d resources from %s", len(objs), dir)
+	applied, err := applyResources(ctx, client, mapper, namespace, objs)
+	if werr := writeAppliedResources(o.IOStreams.Out, applied); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// readApplyManifests returns the resources in the YAML files in dir with a kind in applyKinds.
+func readApplyManifests(dir string) ([]*unstructured.Unstructured, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var objs []*unstructured.Unstructured
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		dec := yaml.NewYAMLOrJSONDecoder(f, 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			err := dec.Decode(&obj.Object)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("error decoding %s: %v", path, err)
+			}
+			// Skip empty documents and snippets that are not API objects.
+			if obj.Object == nil || obj.GetKind() == "" {
+				continue
+			}
+			if _, ok := applyKinds[obj.GetKind()]; !ok {
+				logrus.Debugf("Skipping %s %q from %s", obj.GetKind(), obj.GetName(), path)
+				continue
+			}
+			objs = append(objs, obj)
+		}
+		f.Close()
+	}
+	return objs, nil
+}
+
+// applyResources server-side applies objs and returns the status of each applied object.
+// Namespaced objects without a namespace are applied to defaultNamespace.
+func applyResources(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, defaultNamespace string, objs []*unstructured.Unstructured) ([]appliedResource, error) {
+	var applied []appliedResource
+	var errs []error
+	force := true
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error finding resource for %s %q: %v", gvk.Kind, obj.GetName(), err))
+			continue
+		}
+
+		var resource dynamic.ResourceInterface = client.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(defaultNamespace)
+			}
+			resource = client.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result, err := resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: applyFieldManager,
+			Force:        &force,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error applying %s %q: %v", gvk.Kind, obj.GetName(), err))
+			continue
+		}
+		applied = append(applied, appliedResource{
+			kind:      result.GetKind(),
+			namespace: result.GetNamespace(),
+			name:      result.GetName(),
+			status:    resourceStatus(result),
+		})
+	}
+	return applied, utilerrors.NewAggregate(errs)
+}
+
+// resourceStatus summarizes the status reported by obj.
+func resourceStatus(obj *unstructured.Unstructured) string {
+	// CatalogSources report the state of their registry connection.
+	if state, found, _ := unstructured.NestedString(obj.Object, "status", "connectionState", "lastObservedState"); found {
+		return state
+	}
+	conditions, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || len(conditions) == 0 {
+		return "Applied"
+	}
+	var statuses []string
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		statuses = append(statuses, fmt.Sprintf("%v=%v", condition["type"], condition["status"]))
+	}
+	return strings.Join(statuses, ",")
+}
+
+// writeAppliedResources prints the applied resources and their status.
+func writeAppliedResources(w io.Writer, applied []appliedResource) error {
+	if len(applied) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tSTATUS"); err != nil {
+		return err
+	}
+	for _, r := range applied {
+		namespace := r.namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.kind, namespace, r.name, r.status); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,111 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestApplyResources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"imageContentSourcePolicy.yaml": `---
+apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  name: release-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - mirror.io/openshift/release
+    source: quay.io/openshift-release-dev/ocp-release
+`,
+		"catalogSource-redhat-operator-index.yaml": `apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: redhat-operator-index
+  namespace: openshift-marketplace
+spec:
+  image: mirror.io/redhat/redhat-operator-index:v4.10
+  sourceType: grpc
+`,
+		installConfigFile: `imageContentSources:
+- mirrors:
+  - mirror.io/openshift/release
+  source: quay.io/openshift-release-dev/ocp-release
+`,
+		machineConfigFile: `---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  name: 99-worker-mirror-registries
+`,
+	}
+	for name, data := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+	}
+
+	objs, err := readApplyManifests(dir)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	require.Equal(t, "CatalogSource", objs[0].GetKind())
+	require.Equal(t, icspKind, objs[1].GetKind())
+
+	icspGVK := schema.GroupVersionKind{Group: "operator.openshift.io", Version: "v1alpha1", Kind: icspKind}
+	csGVK := schema.GroupVersionKind{Group: "operators.coreos.com", Version: "v1alpha1", Kind: "CatalogSource"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(icspGVK, meta.RESTScopeRoot)
+	mapper.Add(csGVK, meta.RESTScopeNamespace)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	var patched []string
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		require.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		if obj.GetKind() == "CatalogSource" {
+			if err := unstructured.SetNestedField(obj.Object, "READY", "status", "connectionState", "lastObservedState"); err != nil {
+				return true, nil, err
+			}
+		}
+		patched = append(patched, patch.GetNamespace()+"/"+patch.GetName())
+		return true, obj, nil
+	})
+
+	applied, err := applyResources(context.Background(), client, mapper, "default", objs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"openshift-marketplace/redhat-operator-index", "/release-0"}, patched)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAppliedResources(&buf, applied))
+	require.Equal(t, "KIND                      NAMESPACE              NAME                   STATUS\n"+
+		"CatalogSource             openshift-marketplace  redhat-operator-index  READY\n"+
+		"ImageContentSourcePolicy  -                      release-0              Applied\n", buf.String())
+}
+
+func TestResourceStatus(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "ReconcileCompleted", "status": "True"},
+				map[string]interface{}{"type": "RetrievedUpdates", "status": "False"},
+			},
+		},
+	}}
+	require.Equal(t, "ReconcileCompleted=True,RetrievedUpdates=False", resourceStatus(obj))
+}
@@ -0,0 +1,220 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/bundle"
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// maxArtifactRequests is the maximum number of images
+// concurrently searched for related artifacts.
+const maxArtifactRequests = 8
+
+// cosignTagSuffixes are the suffixes of the tags Sigstore tooling attaches
+// signatures, attestations, and SBOMs to. The empty suffix is the OCI
+// referrers tag schema used by registries without the referrers API.
+var cosignTagSuffixes = []string{"", ".sig", ".att", ".sbom"}
+
+// relatedArtifact is an artifact that refers to a planned image
+// by either a digest-derived tag or its subject descriptor.
+type relatedArtifact struct {
+	// tag of the artifact, empty for artifacts only referenced by digest.
+	tag    string
+	digest string
+}
+
+// planRelatedArtifacts discovers the Sigstore signatures, attestations, SBOMs, and OCI
+// referrers attached to the registry sources in mapping and returns a mapping for them.
+// Artifacts are mirrored to the same repository as the image they are attached to so
+// they can be found by digest on the mirror. Images that cannot be inspected are logged
+// and skipped.
+func (o *MirrorOptions) planRelatedArtifacts(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, mapping image.TypedImageMapping) image.TypedImageMapping {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, maxArtifactRequests)
+		artifacts = image.TypedImageMapping{}
+	)
+
+	remoteOpts := o.getSourceRemoteOpts(ctx)
+	for srcRef, dstRef := range mapping {
+		// Catalogs are rebuilt, so artifacts attached to
+		// the source catalog do not apply to the mirror.
+		if srcRef.Type != imagesource.DestinationRegistry || srcRef.Category == image.TypeOperatorCatalog ||
+			bundle.IsBlocked(cfg, srcRef.Ref) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(srcRef, dstRef image.TypedImage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			found, err := o.findRelatedArtifacts(ctx, srcRef, remoteOpts...)
+			if err != nil {
+				logrus.Warnf("unable to find related artifacts for image %s: %v", srcRef.Ref.Exact(), err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, artifact := range found {
+				src, dst := srcRef, dstRef
+				src.Ref.Tag, src.Ref.ID = artifact.tag, artifact.digest
+				dst.Ref.Tag, dst.Ref.ID = artifact.tag, ""
+				if artifact.tag == "" {
+					dst.Ref.ID = artifact.digest
+				}
+				logrus.Debugf("Found artifact %s for image %s", src.Ref.Exact(), srcRef.Ref.Exact())
+				artifacts[src] = dst
+			}
+		}(srcRef, dstRef)
+	}
+	wg.Wait()
+
+	if len(artifacts) != 0 {
+		logrus.Infof("Found %d related artifacts for planned images", len(artifacts))
+	}
+	return artifacts
+}
+
+// findRelatedArtifacts returns the artifacts tagged with the digest of img
+// and the referrers of img, sorted by tag and digest.
+func (o *MirrorOptions) findRelatedArtifacts(ctx context.Context, img image.TypedImage, opts ...remote.Option) ([]relatedArtifact, error) {
+	repo, err := name.NewRepository(img.Ref.AsRepository().Exact(), o.getSourceNameOpts()...)
+	if err != nil {
+		return nil, err
+	}
+	dgst := img.Ref.ID
+	if dgst == "" {
+		desc, err := remote.Head(repo.Tag(img.Ref.Tag), opts...)
+		if err != nil {
+			return nil, err
+		}
+		dgst = desc.Digest.String()
+	}
+	tagPrefix := strings.Replace(dgst, ":", "-", 1)
+
+	seen := map[string]struct{}{}
+	var artifacts []relatedArtifact
+	var fallback *v1.Descriptor
+	for _, suffix := range cosignTagSuffixes {
+		tag := tagPrefix + suffix
+		desc, err := remote.Head(repo.Tag(tag), opts...)
+		switch {
+		case isNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		artifacts = append(artifacts, relatedArtifact{tag: tag, digest: desc.Digest.String()})
+		seen[desc.Digest.String()] = struct{}{}
+		if suffix == "" {
+			fallback = desc
+		}
+	}
+
+	referrers, err := o.fetchReferrers(ctx, repo.Digest(dgst))
+	if err != nil {
+		return nil, err
+	}
+	if referrers == nil && fallback != nil && fallback.MediaType.IsIndex() {
+		// The registry does not implement the referrers API,
+		// so referrers are listed in the referrers tag.
+		idx, err := remote.Index(repo.Digest(fallback.Digest.String()), opts...)
+		if err != nil {
+			return nil, err
+		}
+		referrers, err = idx.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if referrers != nil {
+		for _, desc := range referrers.Manifests {
+			if _, found := seen[desc.Digest.String()]; found {
+				continue
+			}
+			seen[desc.Digest.String()] = struct{}{}
+			artifacts = append(artifacts, relatedArtifact{digest: desc.Digest.String()})
+		}
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].tag != artifacts[j].tag {
+			return artifacts[i].tag < artifacts[j].tag
+		}
+		return artifacts[i].digest < artifacts[j].digest
+	})
+	return artifacts, nil
+}
+
+// fetchReferrers queries the OCI referrers API for the artifacts whose subject
+// is ref. A nil index is returned if the registry does not support the API.
+func (o *MirrorOptions) fetchReferrers(ctx context.Context, ref name.Digest) (*v1.IndexManifest, error) {
+	repo := ref.Context()
+	auth, err := authn.DefaultKeychain.Resolve(repo)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := transport.NewWithContext(ctx, repo.Registry, auth, newTransport(o.SourcePlainHTTP || o.SourceSkipTLS),
+		[]string{repo.Scope(transport.PullScope)})
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s://%s/v2/%s/referrers/%s", repo.Registry.Scheme(), repo.RegistryStr(), repo.RepositoryStr(), ref.DigestStr())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(types.OCIImageIndex))
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Registries without the referrers API respond
+		// with a variety of client errors.
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		logrus.Debugf("referrers API not available for %s: %s", repo, resp.Status)
+		return nil, nil
+	}
+	// Registries implementing the API must respond with an index.
+	if mt := resp.Header.Get("Content-Type"); !strings.HasPrefix(mt, string(types.OCIImageIndex)) {
+		logrus.Debugf("referrers API not available for %s: unexpected content type %q", repo, mt)
+		return nil, nil
+	}
+	return v1.ParseIndexManifest(resp.Body)
+}
+
+// isNotFound reports whether err is a registry response
+// for a manifest that does not exist.
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+	return terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,139 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestPlanRelatedArtifacts(t *testing.T) {
+	type spec struct {
+		name         string
+		referrersAPI bool
+	}
+
+	cases := []spec{
+		{name: "Valid/ReferrersTagSchema"},
+		{name: "Valid/ReferrersAPI", referrersAPI: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			reg := registry.New()
+			var referrers []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if c.referrersAPI && strings.Contains(r.URL.Path, "/referrers/") {
+					w.Header().Set("Content-Type", string(types.OCIImageIndex))
+					_, _ = w.Write(referrers)
+					return
+				}
+				reg.ServeHTTP(w, r)
+			}))
+			t.Cleanup(server.Close)
+			u, err := url.Parse(server.URL)
+			require.NoError(t, err)
+			repo := fmt.Sprintf("%s/test/image", u.Host)
+
+			writeImage := func(ref string, contents string) v1.Hash {
+				img, err := crane.Image(map[string][]byte{"foo": []byte(contents)})
+				require.NoError(t, err)
+				r, err := name.ParseReference(ref)
+				require.NoError(t, err)
+				require.NoError(t, remote.Write(r, img))
+				dgst, err := img.Digest()
+				require.NoError(t, err)
+				return dgst
+			}
+
+			subject := writeImage(repo+":latest", "subject")
+			tagPrefix := strings.Replace(subject.String(), ":", "-", 1)
+			sig := writeImage(repo+":"+tagPrefix+".sig", "signature")
+			sbom := writeImage(repo+":"+tagPrefix+".sbom", "sbom")
+
+			referrerImg, err := crane.Image(map[string][]byte{"foo": []byte("referrer")})
+			require.NoError(t, err)
+			referrerDigest, err := referrerImg.Digest()
+			require.NoError(t, err)
+			referrerRef, err := name.ParseReference(repo + "@" + referrerDigest.String())
+			require.NoError(t, err)
+			require.NoError(t, remote.Write(referrerRef, referrerImg))
+			idx := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: referrerImg})
+			idx = mutate.IndexMediaType(idx, types.OCIImageIndex)
+
+			exp := image.TypedImageMapping{}
+			add := func(tag, dgst string) {
+				ref := fmt.Sprintf("%s@%s", repo, dgst)
+				if tag != "" {
+					ref = fmt.Sprintf("%s:%s@%s", repo, tag, dgst)
+				}
+				src, err := image.ParseTypedImage(ref, image.TypeGeneric)
+				require.NoError(t, err)
+				dst := src
+				dst.Type = imagesource.DestinationFile
+				dst.Ref.Registry = ""
+				if tag != "" {
+					dst.Ref.ID = ""
+				}
+				exp[src] = dst
+			}
+			add(tagPrefix+".sbom", sbom.String())
+			add(tagPrefix+".sig", sig.String())
+			add("", referrerDigest.String())
+
+			if c.referrersAPI {
+				referrers, err = idx.RawManifest()
+				require.NoError(t, err)
+			} else {
+				fallbackRef, err := name.ParseReference(repo + ":" + tagPrefix)
+				require.NoError(t, err)
+				require.NoError(t, remote.WriteIndex(fallbackRef, idx))
+				fallback, err := idx.Digest()
+				require.NoError(t, err)
+				add(tagPrefix, fallback.String())
+			}
+
+			src, err := image.ParseTypedImage(repo+"@"+subject.String(), image.TypeGeneric)
+			require.NoError(t, err)
+			dst := src
+			dst.Type = imagesource.DestinationFile
+			dst.Ref.Registry = ""
+			mapping := image.TypedImageMapping{src: dst}
+
+			opts := &MirrorOptions{SourcePlainHTTP: true}
+			artifacts := opts.planRelatedArtifacts(context.Background(), v1alpha2.ImageSetConfiguration{}, mapping)
+			require.Equal(t, exp, artifacts)
+		})
+	}
+}
+
+func TestFetchReferrersUnsupported(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/image@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001", u.Host))
+	require.NoError(t, err)
+	opts := &MirrorOptions{SourcePlainHTTP: true}
+	referrers, err := opts.fetchReferrers(context.Background(), ref)
+	require.NoError(t, err)
+	require.Nil(t, referrers)
+}
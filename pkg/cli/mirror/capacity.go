@@ -0,0 +1,381 @@
+package mirror
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/mholt/archiver/v3"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/archive"
+	"github.com/openshift/oc-mirror/pkg/bundle"
+	"github.com/openshift/oc-mirror/pkg/config"
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// maxSizeRequests is the maximum number of concurrent
+// manifest requests made while estimating image sizes.
+const maxSizeRequests = 8
+
+// errCapacityUnsupported is returned when free space
+// cannot be determined on the current platform.
+var errCapacityUnsupported = errors.New("checking free disk space is not supported on this platform")
+
+// statDisk returns the available bytes and device ID of the filesystem
+// containing path or its nearest existing parent. It is a variable so it
+// can be replaced in tests.
+var statDisk = diskStat
+
+// ErrInsufficientDiskSpace is returned when a filesystem
+// does not have enough free space for a mirror operation.
+type ErrInsufficientDiskSpace struct {
+	paths     []string
+	required  uint64
+	available uint64
+}
+
+func (e *ErrInsufficientDiskSpace) Error() string {
+	return fmt.Sprintf("insufficient disk space for %s: an estimated %s is required but only %s is available "+
+		"(use --skip-capacity-check to bypass this check)",
+		strings.Join(e.paths, ", "), formatBytes(e.required), formatBytes(e.available))
+}
+
+// diskRequirement is the number of bytes that must be
+// available on the filesystem containing path.
+type diskRequirement struct {
+	path  string
+	bytes uint64
+}
+
+// blobSizes maps manifest and blob digests to their size in bytes.
+type blobSizes map[string]int64
+
+// checkCreateCapacity estimates the size of the images in mapping that have not been
+// mirrored in a previous run and verifies the workspace and output directories can
+// hold the downloaded images and resulting archives.
+func (o *MirrorOptions) checkCreateCapacity(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata, mapping image.TypedImageMapping) error {
+	logrus.Info("Estimating imageset size")
+	sizes := o.imageSizes(ctx, cfg, mapping)
+	required := uint64(totalSize(sizes, meta.PastBlobs))
+	logrus.Infof("Estimated imageset size is %s", formatBytes(required))
+
+	archiveBytes := required
+	same, err := sameFilesystem(o.Dir, o.OutputDir)
+	if err == nil && same && !o.SkipCleanup {
+		// Blobs are removed from the workspace as they are archived, so only
+		// a single archive segment must fit alongside the downloaded images.
+		segSize := uint64(segmentSize(cfg.ArchiveSize))
+		if segSize < archiveBytes {
+			archiveBytes = segSize
+		}
+	}
+
+	return checkDiskSpace(
+		diskRequirement{path: o.Dir, bytes: required},
+		diskRequirement{path: o.OutputDir, bytes: archiveBytes},
+	)
+}
+
+// checkPublishCapacity verifies the workspace can hold the contents of the
+// imageset archives being published and the results directory can hold the
+// Helm charts, release signatures, and release tools written to it.
+func (o *MirrorOptions) checkPublishCapacity() error {
+	a := archive.NewArchiver()
+	required, err := archiveSize(a, o.From)
+	if err != nil {
+		return err
+	}
+	results, err := resultsSize(a, o.From)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("Imageset archives total %s, %s of which are written to %s",
+		formatBytes(required), formatBytes(results), o.OutputDir)
+	return checkDiskSpace(
+		diskRequirement{path: o.Dir, bytes: required},
+		diskRequirement{path: o.OutputDir, bytes: results},
+	)
+}
+
+// imageSizes fetches the manifests of all registry sources in mapping and returns the
+// sizes of each manifest, config, and layer blob per image. Images that cannot be
+// inspected are logged and excluded from the estimate.
+func (o *MirrorOptions) imageSizes(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, mapping image.TypedImageMapping) map[image.TypedImage]blobSizes {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, maxSizeRequests)
+		sizes = make(map[image.TypedImage]blobSizes, len(mapping))
+	)
+
+	remoteOpts := o.getSourceRemoteOpts(ctx)
+	for srcRef := range mapping {
+		if srcRef.Type != imagesource.DestinationRegistry || bundle.IsBlocked(cfg, srcRef.Ref) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(srcRef image.TypedImage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ref, err := name.ParseReference(srcRef.Ref.Exact(), o.getSourceNameOpts()...)
+			if err != nil {
+				logrus.Warnf("unable to estimate size of image %s: %v", srcRef.Ref.Exact(), err)
+				return
+			}
+			imgSizes, err := fetchBlobSizes(ref, remoteOpts...)
+			if err != nil {
+				logrus.Warnf("unable to estimate size of image %s: %v", srcRef.Ref.Exact(), err)
+				return
+			}
+			mu.Lock()
+			sizes[srcRef] = imgSizes
+			mu.Unlock()
+		}(srcRef)
+	}
+	wg.Wait()
+
+	return sizes
+}
+
+// fetchBlobSizes returns the sizes of the manifests and blobs that
+// make up ref, including all child manifests of an index.
+func fetchBlobSizes(ref name.Reference, opts ...remote.Option) (blobSizes, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sizes := blobSizes{desc.Digest.String(): desc.Size}
+
+	if !desc.MediaType.IsIndex() {
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+		return sizes, addImageSizes(img, sizes)
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range idxManifest.Manifests {
+		sizes[child.Digest.String()] = child.Size
+		if !child.MediaType.IsImage() {
+			continue
+		}
+		img, err := idx.Image(child.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if err := addImageSizes(img, sizes); err != nil {
+			return nil, err
+		}
+	}
+	return sizes, nil
+}
+
+func addImageSizes(img v1.Image, sizes blobSizes) error {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+	sizes[manifest.Config.Digest.String()] = manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		sizes[layer.Digest.String()] = layer.Size
+	}
+	return nil
+}
+
+// totalSize returns the size of all unique blobs in sizes
+// that are not already present in pastBlobs.
+func totalSize(sizes map[image.TypedImage]blobSizes, pastBlobs []v1alpha2.Blob) int64 {
+	seen := make(map[string]struct{}, len(pastBlobs))
+	for _, blob := range pastBlobs {
+		seen[blob.ID] = struct{}{}
+	}
+	var total int64
+	for _, imgSizes := range sizes {
+		for digest, size := range imgSizes {
+			if _, found := seen[digest]; found {
+				continue
+			}
+			seen[digest] = struct{}{}
+			total += size
+		}
+	}
+	return total
+}
+
+// resultsDirs are the top-level imageset directories
+// written to the results directory at publish.
+var resultsDirs = map[string]struct{}{
+	config.HelmDir:              {},
+	config.ReleaseSignaturesDir: {},
+	config.ToolsDir:             {},
+}
+
+// archiveSize returns the total size of the imageset archives at path.
+func archiveSize(a archive.Archiver, path string) (uint64, error) {
+	archives, err := imageSetArchives(a, path)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	for _, info := range archives {
+		total += uint64(info.Size())
+	}
+	return total, nil
+}
+
+// resultsSize returns the total size of the files in the imageset
+// archives at path that are written to the results directory at publish.
+func resultsSize(a archive.Archiver, path string) (uint64, error) {
+	archives, err := imageSetArchives(a, path)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	for arc := range archives {
+		err := a.Walk(arc, func(f archiver.File) error {
+			header, ok := f.Header.(*tar.Header)
+			if !ok {
+				return fmt.Errorf("expected header to be *tar.Header but was %T", f.Header)
+			}
+			top := strings.SplitN(header.Name, "/", 2)[0]
+			if _, found := resultsDirs[top]; found && !f.IsDir() {
+				total += uint64(header.Size)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// imageSetArchives returns the imageset archives at path,
+// which is either an archive or a directory of archives.
+func imageSetArchives(a archive.Archiver, path string) (map[string]os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return map[string]os.FileInfo{path: info}, nil
+	}
+	archives := map[string]os.FileInfo{}
+	err = filepath.Walk(path, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() && strings.TrimPrefix(filepath.Ext(fpath), ".") == a.String() {
+			archives[fpath] = info
+		}
+		return nil
+	})
+	return archives, err
+}
+
+// checkDiskSpace verifies each filesystem has enough free space for the
+// combined requirements of all paths it contains.
+func checkDiskSpace(reqs ...diskRequirement) error {
+	type filesystem struct {
+		paths     []string
+		required  uint64
+		available uint64
+	}
+	var devices []uint64
+	filesystems := map[uint64]*filesystem{}
+	for _, req := range reqs {
+		if req.path == "" {
+			continue
+		}
+		available, device, err := statDisk(req.path)
+		switch {
+		case errors.Is(err, errCapacityUnsupported):
+			logrus.Warn(err)
+			return nil
+		case err != nil:
+			return fmt.Errorf("error checking free disk space for %s: %v", req.path, err)
+		}
+		fs, found := filesystems[device]
+		if !found {
+			fs = &filesystem{available: available}
+			filesystems[device] = fs
+			devices = append(devices, device)
+		}
+		fs.paths = append(fs.paths, req.path)
+		fs.required += req.bytes
+	}
+
+	for _, device := range devices {
+		fs := filesystems[device]
+		logrus.Debugf("Filesystem for %s requires %s, %s available",
+			strings.Join(fs.paths, ", "), formatBytes(fs.required), formatBytes(fs.available))
+		if fs.required > fs.available {
+			return &ErrInsufficientDiskSpace{fs.paths, fs.required, fs.available}
+		}
+	}
+	return nil
+}
+
+// sameFilesystem reports whether paths a and b reside on the same filesystem.
+func sameFilesystem(a, b string) (bool, error) {
+	_, devA, err := statDisk(a)
+	if err != nil {
+		return false, err
+	}
+	_, devB, err := statDisk(b)
+	if err != nil {
+		return false, err
+	}
+	return devA == devB, nil
+}
+
+// existingParent returns path or its nearest existing parent directory.
+func existingParent(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// formatBytes returns a human readable representation of b using binary units.
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package mirror
+
+func diskStat(path string) (available uint64, device uint64, err error) {
+	return 0, 0, errCapacityUnsupported
+}
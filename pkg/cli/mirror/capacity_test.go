@@ -0,0 +1,193 @@
+package mirror
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/cli"
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestCheckDiskSpace(t *testing.T) {
+	type spec struct {
+		name     string
+		disks    map[string][2]uint64
+		reqs     []diskRequirement
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:  "Valid/SeparateFilesystems",
+			disks: map[string][2]uint64{"/ws": {100, 1}, "/out": {100, 2}},
+			reqs: []diskRequirement{
+				{path: "/ws", bytes: 100},
+				{path: "/out", bytes: 100},
+			},
+		},
+		{
+			name:  "Invalid/SharedFilesystem",
+			disks: map[string][2]uint64{"/ws": {150, 1}, "/out": {150, 1}},
+			reqs: []diskRequirement{
+				{path: "/ws", bytes: 100},
+				{path: "/out", bytes: 100},
+			},
+			expError: "insufficient disk space for /ws, /out: an estimated 200 B is required " +
+				"but only 150 B is available (use --skip-capacity-check to bypass this check)",
+		},
+		{
+			name:  "Invalid/Workspace",
+			disks: map[string][2]uint64{"/ws": {2048, 1}},
+			reqs: []diskRequirement{
+				{path: "/ws", bytes: 3 * 1024 * 1024 * 1024},
+			},
+			expError: "insufficient disk space for /ws: an estimated 3.0 GiB is required " +
+				"but only 2.0 KiB is available (use --skip-capacity-check to bypass this check)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			statDisk = func(path string) (uint64, uint64, error) {
+				disk, ok := c.disks[path]
+				if !ok {
+					return 0, 0, fmt.Errorf("unknown path %s", path)
+				}
+				return disk[0], disk[1], nil
+			}
+			t.Cleanup(func() { statDisk = diskStat })
+
+			err := checkDiskSpace(c.reqs...)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPublishCapacity(t *testing.T) {
+	type spec struct {
+		name  string
+		disks func(archive uint64) map[string][2]uint64
+		// expError is formatted with the required bytes.
+		expError string
+	}
+
+	dir := t.TempDir()
+	from := filepath.Join(dir, "mirror_seq1_000000.tar")
+	f, err := os.Create(from)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	for name, size := range map[string]int{"charts/foo.tgz": 1024, "tools/sha256-abc/oc.tar.gz": 1024, "v2/foo/blobs/sha256:abc": 4096} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(size)}))
+		_, err := tw.Write(make([]byte, size))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+	info, err := os.Stat(from)
+	require.NoError(t, err)
+	archiveBytes := uint64(info.Size())
+
+	cases := []spec{
+		{
+			name: "Valid/SeparateFilesystems",
+			disks: func(archive uint64) map[string][2]uint64 {
+				return map[string][2]uint64{"/ws": {archive, 1}, "/out": {2048, 2}}
+			},
+		},
+		{
+			name: "Invalid/ResultsDir",
+			disks: func(archive uint64) map[string][2]uint64 {
+				return map[string][2]uint64{"/ws": {archive, 1}, "/out": {1024, 2}}
+			},
+			expError: "insufficient disk space for /out: an estimated 2.0 KiB is required " +
+				"but only 1.0 KiB is available (use --skip-capacity-check to bypass this check)",
+		},
+		{
+			name: "Invalid/SharedFilesystem",
+			disks: func(archive uint64) map[string][2]uint64 {
+				return map[string][2]uint64{"/ws": {archive, 1}, "/out": {archive, 1}}
+			},
+			expError: fmt.Sprintf("insufficient disk space for /ws, /out: an estimated %s is required "+
+				"but only %s is available (use --skip-capacity-check to bypass this check)",
+				formatBytes(archiveBytes+2048), formatBytes(archiveBytes)),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			disks := c.disks(archiveBytes)
+			statDisk = func(path string) (uint64, uint64, error) {
+				disk, ok := disks[path]
+				if !ok {
+					return 0, 0, fmt.Errorf("unknown path %s", path)
+				}
+				return disk[0], disk[1], nil
+			}
+			t.Cleanup(func() { statDisk = diskStat })
+
+			o := &MirrorOptions{From: from, OutputDir: "/out"}
+			o.RootOptions = &cli.RootOptions{Dir: "/ws"}
+			err := o.checkPublishCapacity()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestImageSizes(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	img, err := crane.Image(map[string][]byte{"foo": []byte("bar")})
+	require.NoError(t, err)
+	ref, err := name.ParseReference(fmt.Sprintf("%s/test/image:latest", u.Host))
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	manifest, err := img.Manifest()
+	require.NoError(t, err)
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	rawManifest, err := img.RawManifest()
+	require.NoError(t, err)
+
+	src, err := image.ParseTypedImage(ref.String(), image.TypeGeneric)
+	require.NoError(t, err)
+	mapping := image.TypedImageMapping{src: src}
+
+	opts := &MirrorOptions{SourcePlainHTTP: true}
+	sizes := opts.imageSizes(context.Background(), v1alpha2.ImageSetConfiguration{}, mapping)
+
+	exp := blobSizes{
+		imgDigest.String():                 int64(len(rawManifest)),
+		manifest.Config.Digest.String():    manifest.Config.Size,
+		manifest.Layers[0].Digest.String(): manifest.Layers[0].Size,
+	}
+	require.Equal(t, exp, sizes[src])
+
+	total := exp[imgDigest.String()] + manifest.Config.Size
+	pastBlobs := []v1alpha2.Blob{{ID: manifest.Layers[0].Digest.String()}}
+	require.Equal(t, total, totalSize(sizes, pastBlobs))
+}
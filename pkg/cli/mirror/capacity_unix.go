@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package mirror
+
+import (
+	"syscall"
+)
+
+func diskStat(path string) (available uint64, device uint64, err error) {
+	path = existingParent(path)
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, 0, err
+	}
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return uint64(fs.Bavail) * uint64(fs.Bsize), uint64(st.Dev), nil
+}
@@ -36,6 +36,41 @@ import (
 	"github.com/openshift/oc/pkg/cli/image/imagesource"
 )
 
+// findCatalogIndexes returns the directories containing file-based catalogs
+// written under dstDir keyed by the source catalog image reference.
+func findCatalogIndexes(dstDir string) (map[imagesource.TypedImageReference]string, error) {
+	dstDir = filepath.Clean(dstDir)
+	indexes := map[imagesource.TypedImageReference]string{}
+	err := filepath.Walk(dstDir, func(fpath string, info fs.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return err
+		}
+
+		slashPath := filepath.ToSlash(fpath)
+		if base := path.Base(slashPath); base == "index.json" {
+			slashPath = strings.TrimPrefix(slashPath, fmt.Sprintf("%s/catalogs/", filepath.ToSlash(dstDir)))
+			regRepoNs, id := path.Split(path.Dir(slashPath))
+			regRepoNs = path.Clean(regRepoNs)
+			var img string
+			if strings.Contains(id, ":") {
+				// Digest.
+				img = fmt.Sprintf("%s@%s", regRepoNs, id)
+			} else {
+				// Tag.
+				img = fmt.Sprintf("%s:%s", regRepoNs, id)
+			}
+			sourceRef, err := imagesource.ParseReference(img)
+			if err != nil {
+				return fmt.Errorf("error parsing index dir path %q as image %q: %v", fpath, img, err)
+			}
+			indexes[sourceRef] = filepath.Dir(fpath)
+		}
+
+		return nil
+	})
+	return indexes, err
+}
+
 // unpackCatalog will unpack file-based catalogs if they exists
 func (o *MirrorOptions) unpackCatalog(dstDir string, filesInArchive map[string]string) (bool, error) {
 	var found bool
@@ -51,53 +86,58 @@ func (o *MirrorOptions) unpackCatalog(dstDir string, filesInArchive map[string]s
 	return found, nil
 }
 
-func (o *MirrorOptions) rebuildCatalogs(ctx context.Context, dstDir string) (image.TypedImageMapping, error) {
+// catalogMirrorRefs maps the source catalog images of the file-based catalogs
+// under dstDir to their destination in the mirror registry. The directory of each
+// file-based catalog is returned keyed by its destination reference.
+func (o *MirrorOptions) catalogMirrorRefs(dstDir string) (image.TypedImageMapping, map[imagesource.TypedImageReference]string, error) {
 	refs := image.TypedImageMapping{}
 	var err error
 
 	mirrorRef := imagesource.TypedImageReference{Type: imagesource.DestinationRegistry}
 	mirrorRef.Ref, err = reference.Parse(o.ToMirror)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	dstDir = filepath.Clean(dstDir)
+	indexes, err := findCatalogIndexes(dstDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	catalogsByImage := map[imagesource.TypedImageReference]string{}
-	if err := filepath.Walk(dstDir, func(fpath string, info fs.FileInfo, err error) error {
-		if err != nil || info == nil || info.IsDir() {
-			return err
-		}
+	for sourceRef, dcDir := range indexes {
+		ctlgRef := imagesource.TypedImageReference{Type: imagesource.DestinationRegistry}
+		ctlgRef.Ref = sourceRef.Ref
+		// Update registry so the existing catalog image can be pulled.
+		ctlgRef.Ref.Registry = mirrorRef.Ref.Registry
+		ctlgRef.Ref.Namespace = path.Join(o.UserNamespace, ctlgRef.Ref.Namespace)
+		catalogsByImage[ctlgRef] = dcDir
+
+		// Add to mapping for ICSP generation
+		refs.Add(sourceRef, ctlgRef, image.TypeOperatorCatalog)
+	}
+	return refs, catalogsByImage, nil
+}
 
-		slashPath := filepath.ToSlash(fpath)
-		if base := path.Base(slashPath); base == "index.json" {
-			slashPath = strings.TrimPrefix(slashPath, fmt.Sprintf("%s/catalogs/", dstDir))
-			regRepoNs, id := path.Split(path.Dir(slashPath))
-			regRepoNs = path.Clean(regRepoNs)
-			var img string
-			if strings.Contains(id, ":") {
-				// Digest.
-				img = fmt.Sprintf("%s@%s", regRepoNs, id)
-			} else {
-				// Tag.
-				img = fmt.Sprintf("%s:%s", regRepoNs, id)
-			}
-			ctlgRef := imagesource.TypedImageReference{Type: imagesource.DestinationRegistry}
-			sourceRef, err := imagesource.ParseReference(img)
-			if err != nil {
-				return fmt.Errorf("error parsing index dir path %q as image %q: %v", fpath, img, err)
-			}
-			ctlgRef.Ref = sourceRef.Ref
-			// Update registry so the existing catalog image can be pulled.
-			ctlgRef.Ref.Registry = mirrorRef.Ref.Registry
-			ctlgRef.Ref.Namespace = path.Join(o.UserNamespace, ctlgRef.Ref.Namespace)
-			catalogsByImage[ctlgRef] = filepath.Dir(fpath)
-
-			// Add to mapping for ICSP generation
-			refs.Add(sourceRef, ctlgRef, image.TypeOperatorCatalog)
-		}
+// planCatalogs reports the catalog images that would be rebuilt from the
+// file-based catalogs under dstDir without contacting the mirror registry.
+func (o *MirrorOptions) planCatalogs(dstDir string) (image.TypedImageMapping, error) {
+	refs, catalogsByImage, err := o.catalogMirrorRefs(dstDir)
+	if err != nil {
+		return nil, err
+	}
+	for ctlgRef := range catalogsByImage {
+		logrus.Infof("Dry run: would rebuild catalog image %q", ctlgRef.Ref.Exact())
+	}
+	return refs, nil
+}
 
-		return nil
-	}); err != nil {
+func (o *MirrorOptions) rebuildCatalogs(ctx context.Context, dstDir string) (image.TypedImageMapping, error) {
+	// Dry runs only plan catalog images so the mirror registry is not contacted.
+	if o.DryRun {
+		return o.planCatalogs(dstDir)
+	}
+	refs, catalogsByImage, err := o.catalogMirrorRefs(dstDir)
+	if err != nil {
 		return nil, err
 	}
 
@@ -200,7 +240,7 @@ func (o *MirrorOptions) rebuildCatalogs(ctx context.Context, dstDir string) (ima
 				return refs, fmt.Errorf("error parsing image %q: %v", OPMImage, err)
 			}
 
-			opmImage.Registry = mirrorRef.Ref.Registry
+			opmImage.Registry = ctlgRef.Ref.Registry
 			opmImage.Namespace = path.Join(o.UserNamespace, opmImage.Namespace)
 			srcImage = opmImage.Exact()
 
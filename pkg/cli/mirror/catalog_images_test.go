@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/image"
 )
 
 // TODO: Pull image for manifest and index checks
@@ -46,3 +49,24 @@ func TestBuildCatalogLayer(t *testing.T) {
 	}
 	require.NoError(t, o.buildCatalogLayer(context.Background(), targetRef, targetRef, t.TempDir(), []v1.Layer{add, delete}...))
 }
+
+func TestPlanCatalogs(t *testing.T) {
+	tmpdir := t.TempDir()
+	indexDir := filepath.Join(tmpdir, "catalogs", "registry.redhat.io", "redhat", "redhat-operator-index", "v4.10")
+	require.NoError(t, os.MkdirAll(indexDir, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(indexDir, "index.json"), []byte("{}"), 0644))
+
+	opts := &MirrorOptions{
+		ToMirror:      "localhost:5000",
+		UserNamespace: "disconnected",
+	}
+	refs, err := opts.planCatalogs(tmpdir)
+	require.NoError(t, err)
+
+	require.Len(t, refs, 1)
+	for src, dst := range refs {
+		require.Equal(t, "registry.redhat.io/redhat/redhat-operator-index:v4.10", src.Ref.Exact())
+		require.Equal(t, "localhost:5000/disconnected/redhat/redhat-operator-index:v4.10", dst.Ref.Exact())
+		require.Equal(t, image.TypeOperatorCatalog, src.Category)
+	}
+}
@@ -69,7 +69,7 @@ func (o *MirrorOptions) Create(ctx context.Context, cfg v1alpha2.ImageSetConfigu
 			}
 			return image.TypedImageMapping{}, nil
 		}
-		mmapping, err := o.run(ctx, &cfg, meta, f)
+		mmapping, err := o.run(ctx, &cfg, meta, &thisRun, f)
 		meta.PastMirror = thisRun
 		return meta, mmapping, err
 	default:
@@ -84,25 +84,31 @@ func (o *MirrorOptions) Create(ctx context.Context, cfg v1alpha2.ImageSetConfigu
 			}
 			return image.TypedImageMapping{}, nil
 		}
-		mmapping, err := o.run(ctx, &cfg, meta, f)
+		mmapping, err := o.run(ctx, &cfg, meta, &thisRun, f)
 		meta.PastMirror = thisRun
 		return meta, mmapping, err
 	}
 }
 
-func (o *MirrorOptions) run(ctx context.Context, cfg *v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata, operatorPlan operatorFunc) (image.TypedImageMapping, error) {
+func (o *MirrorOptions) run(ctx context.Context, cfg *v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata, thisRun *v1alpha2.PastMirror, operatorPlan operatorFunc) (image.TypedImageMapping, error) {
 
 	// Ensure meta has the latest OPM image, and if not add it to cfg for mirroring.
 	addOPMImage(cfg, meta)
 	mmappings := image.TypedImageMapping{}
 
-	if len(cfg.Mirror.OCP.Channels) != 0 {
+	if len(cfg.Mirror.OCP.Channels) != 0 || len(cfg.Mirror.OCP.Releases) != 0 {
 		release := NewReleaseOptions(o)
 		mappings, err := release.Plan(ctx, meta.PastMirror, cfg)
 		if err != nil {
 			return mmappings, err
 		}
 		mmappings.Merge(mappings)
+		thisRun.Releases = release.releases
+		thisRun.AddedReleases = release.added
+		thisRun.RemovedReleases = release.removed
+		thisRun.PrunableReleases = release.prunable
+		thisRun.ConditionalReleases = release.conditionalReleases()
+		thisRun.UpgradePaths = release.paths
 	}
 
 	mappings, err := operatorPlan(ctx, *cfg)
@@ -133,6 +139,10 @@ func (o *MirrorOptions) run(ctx context.Context, cfg *v1alpha2.ImageSetConfigura
 		logrus.Debugf("sample images full not implemented")
 	}
 
+	if o.IncludeRelatedArtifacts {
+		mmappings.Merge(o.planRelatedArtifacts(ctx, *cfg, mmappings))
+	}
+
 	return mmappings, nil
 }
 
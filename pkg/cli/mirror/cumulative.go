@@ -0,0 +1,91 @@
+package mirror
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// completeResultsDir is the results subdirectory containing manifests
+// for the images of every published sequence.
+const completeResultsDir = "complete"
+
+// mergeMirroredImages adds the registry destinations in mapping to past. Images
+// already recorded are replaced with their latest destination.
+func mergeMirroredImages(past []v1alpha2.MirroredImage, mapping image.TypedImageMapping) []v1alpha2.MirroredImage {
+	type key struct{ source, typ string }
+	merged := make(map[key]v1alpha2.MirroredImage, len(past)+len(mapping))
+	for _, img := range past {
+		merged[key{img.Source, img.Type}] = img
+	}
+	for src, dst := range mapping {
+		if dst.Type != imagesource.DestinationRegistry {
+			continue
+		}
+		img := v1alpha2.MirroredImage{
+			Source:      src.Ref.Exact(),
+			Destination: dst.Ref.Exact(),
+			Type:        src.Category.String(),
+		}
+		merged[key{img.Source, img.Type}] = img
+	}
+
+	imgs := make([]v1alpha2.MirroredImage, 0, len(merged))
+	for _, img := range merged {
+		imgs = append(imgs, img)
+	}
+	sort.Slice(imgs, func(i, j int) bool {
+		if imgs[i].Source != imgs[j].Source {
+			return imgs[i].Source < imgs[j].Source
+		}
+		return imgs[i].Type < imgs[j].Type
+	})
+	return imgs
+}
+
+// mirroredImagesMapping returns the image mapping recorded in imgs.
+func mirroredImagesMapping(imgs []v1alpha2.MirroredImage) (image.TypedImageMapping, error) {
+	mapping := make(image.TypedImageMapping, len(imgs))
+	for _, img := range imgs {
+		typ, err := image.ParseImageType(img.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mirrored image %s: %v", img.Source, err)
+		}
+		src, err := image.ParseTypedImage(img.Source, typ)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mirrored image %s: %v", img.Source, err)
+		}
+		dst, err := image.ParseTypedImage(img.Destination, typ)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mirrored image %s: %v", img.Destination, err)
+		}
+		mapping[src] = dst
+	}
+	return mapping, nil
+}
+
+// writeCompleteResults writes the CatalogSources and image mirror policies for all
+// images recorded in meta to the complete subdirectory of dir. Unlike the manifests
+// for a single run, applying these never removes mirrors for earlier sequences.
+func (o *MirrorOptions) writeCompleteResults(meta v1alpha2.Metadata, dir string) error {
+	mapping, err := mirroredImagesMapping(meta.MirroredImages)
+	if err != nil {
+		return err
+	}
+	completeDir := filepath.Join(dir, completeResultsDir)
+	if err := os.MkdirAll(completeDir, os.ModePerm); err != nil {
+		return err
+	}
+	logrus.Infof("Writing manifests for all published images to %s", completeDir)
+	if err := WriteCatalogSource(image.ByCategory(mapping, image.TypeOperatorCatalog), completeDir); err != nil {
+		return err
+	}
+	return o.generateAllICSPs(mapping, completeDir)
+}
@@ -0,0 +1,73 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestMergeMirroredImages(t *testing.T) {
+	past := []v1alpha2.MirroredImage{
+		{
+			Source:      "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			Destination: "old.mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			Type:        "ocpRelease",
+		},
+		{
+			Source:      "registry.redhat.io/ubi8/ubi:8.4",
+			Destination: "mirror.io/ubi8/ubi:8.4",
+			Type:        "generic",
+		},
+	}
+
+	mapping := image.TypedImageMapping{}
+	for src, dst := range map[string]string{
+		"quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001": "mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+		"quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002": "mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002",
+	} {
+		srcImg, err := image.ParseTypedImage(src, image.TypeOCPRelease)
+		require.NoError(t, err)
+		dstImg, err := image.ParseTypedImage(dst, image.TypeOCPRelease)
+		require.NoError(t, err)
+		mapping[srcImg] = dstImg
+	}
+	// Images mirrored to disk have no registry destination to record.
+	fileSrc, err := image.ParseTypedImage("registry.redhat.io/ubi8/ubi-minimal:8.4", image.TypeGeneric)
+	require.NoError(t, err)
+	fileDst := fileSrc
+	fileDst.Type = imagesource.DestinationFile
+	mapping[fileSrc] = fileDst
+
+	exp := []v1alpha2.MirroredImage{
+		{
+			Source:      "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			Destination: "mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			Type:        "ocpRelease",
+		},
+		{
+			Source:      "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002",
+			Destination: "mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002",
+			Type:        "ocpRelease",
+		},
+		past[1],
+	}
+	merged := mergeMirroredImages(past, mapping)
+	require.Equal(t, exp, merged)
+
+	// The recorded images must produce the same mapping.
+	got, err := mirroredImagesMapping(merged)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	ubi, err := image.ParseTypedImage("registry.redhat.io/ubi8/ubi:8.4", image.TypeGeneric)
+	require.NoError(t, err)
+	require.Equal(t, "mirror.io/ubi8/ubi:8.4", got[ubi].Ref.Exact())
+	for src, dst := range mapping {
+		if dst.Type == imagesource.DestinationRegistry {
+			require.Equal(t, dst, got[src])
+		}
+	}
+}
@@ -36,6 +36,9 @@ func NewDescribeCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 		Example: templates.Examples(`
 			# Output the contents of 'mirror_seq1_00000.tar'
 			oc-mirror describe mirror_seq1_00000.tar
+
+			# List the images in 'mirror_seq1_00000.tar'
+			oc-mirror describe images --from mirror_seq1_00000.tar
 		`),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -47,6 +50,8 @@ func NewDescribeCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 
 	o.BindFlags(cmd.PersistentFlags())
 
+	cmd.AddCommand(NewImagesCommand(f, ro))
+
 	return cmd
 }
 
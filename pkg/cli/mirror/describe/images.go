@@ -0,0 +1,185 @@
+package describe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc-mirror/pkg/archive"
+	"github.com/openshift/oc-mirror/pkg/bundle"
+	"github.com/openshift/oc-mirror/pkg/cli"
+	"github.com/openshift/oc-mirror/pkg/config"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+type ImagesOptions struct {
+	*cli.RootOptions
+	From string
+}
+
+func NewImagesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
+	o := ImagesOptions{}
+	o.RootOptions = ro
+
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "List the images, tags, and blobs contained in an imageset",
+		Long: templates.LongDesc(`
+			List the images contained in an imageset along with their type, tag, digest,
+			child manifests, and layer count. Images with layers that are not present in the
+			imageset are flagged since those blobs are expected to already exist in the
+			target registry.
+		`),
+		Example: templates.Examples(`
+			# List the images in 'mirror_seq1_00000.tar'
+			oc-mirror describe images --from mirror_seq1_00000.tar
+
+			# List the images in all imageset archives in a directory
+			oc-mirror describe images --from ./archives
+		`),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&o.From, "from", o.From, "Path to an imageset archive or a directory containing imageset archives")
+
+	return cmd
+}
+
+func (o *ImagesOptions) Validate() error {
+	if len(o.From) == 0 {
+		return errors.New("must specify imageset archive location using --from")
+	}
+	return nil
+}
+
+func (o *ImagesOptions) Run(ctx context.Context) error {
+	a := archive.NewArchiver()
+
+	filesInArchive, err := bundle.ReadImageSet(a, o.From)
+	if err != nil {
+		return err
+	}
+
+	// Create workspace to work from
+	tmpdir, err := ioutil.TempDir(".", "associations")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Archives created by older versions contain the legacy associations file.
+	assocPath := config.AssociationsBasePath
+	archive, ok := filesInArchive[config.AssociationsFile]
+	if !ok {
+		assocPath = config.LegacyAssociationsBasePath
+		archive, ok = filesInArchive[config.LegacyAssociationsFile]
+	}
+	if !ok {
+		return errors.New("image associations are not in archive")
+	}
+
+	logrus.Debug("Extracting image associations")
+	if err := a.Extract(archive, assocPath, tmpdir); err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(tmpdir, assocPath))
+	if err != nil {
+		return fmt.Errorf("error opening image associations file: %v", err)
+	}
+	defer f.Close()
+
+	var assocs image.AssociationSet
+	if err := assocs.Decode(f); err != nil {
+		return err
+	}
+
+	return writeImages(o.IOStreams.Out, assocs, filesInArchive)
+}
+
+// writeImages prints each image association in a table. Layers that
+// cannot be found in filesInArchive are counted as missing because they
+// must be pulled from the target registry during publishing.
+func writeImages(w io.Writer, assocs image.AssociationSet, filesInArchive map[string]string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "IMAGE\tTYPE\tTAG\tDIGEST\tMANIFESTS\tLAYERS\tMISSING"); err != nil {
+		return err
+	}
+
+	keys := assocs.Keys()
+	sort.Strings(keys)
+
+	var needsRegistry []string
+	for _, imageName := range keys {
+		values, _ := assocs.Search(imageName)
+		// Print the top-level image first followed by any child manifests.
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Name == imageName || values[j].Name == imageName {
+				return values[i].Name == imageName
+			}
+			return values[i].Name < values[j].Name
+		})
+
+		var missingTotal int
+		for _, assoc := range values {
+			missing := missingLayers(assoc, filesInArchive)
+			missingTotal += len(missing)
+
+			name := assoc.Name
+			if name != imageName {
+				name = "  " + name
+			}
+			tag := assoc.TagSymlink
+			if tag == "" {
+				tag = "-"
+			}
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+				name, assoc.Type, tag, assoc.ID,
+				len(assoc.ManifestDigests), len(assoc.LayerDigests), len(missing)); err != nil {
+				return err
+			}
+		}
+		if missingTotal != 0 {
+			needsRegistry = append(needsRegistry, imageName)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(needsRegistry) != 0 {
+		if _, err := fmt.Fprintf(w, "\nThe following images have blobs that are not in this imageset and must exist in the target registry:\n  %s\n",
+			strings.Join(needsRegistry, "\n  ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingLayers returns the layer digests of assoc that are not in the imageset.
+func missingLayers(assoc image.Association, filesInArchive map[string]string) []string {
+	var missing []string
+	for _, layerDigest := range assoc.LayerDigests {
+		if _, found := filesInArchive[layerDigest]; !found {
+			missing = append(missing, layerDigest)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+package describe
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestWriteImages(t *testing.T) {
+	type spec struct {
+		name           string
+		assocs         image.AssociationSet
+		filesInArchive map[string]string
+		exp            string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/AllBlobsInArchive",
+			assocs: image.AssociationSet{
+				"quay.io/foo/bar:latest": image.Associations{
+					"quay.io/foo/bar:latest": {
+						Name:         "quay.io/foo/bar:latest",
+						Path:         "foo/bar",
+						ID:           "sha256:aaa",
+						TagSymlink:   "latest",
+						Type:         image.TypeGeneric,
+						LayerDigests: []string{"sha256:l1", "sha256:l2"},
+					},
+				},
+			},
+			filesInArchive: map[string]string{
+				"sha256:l1": "mirror_seq1_00000.tar",
+				"sha256:l2": "mirror_seq1_00000.tar",
+			},
+			exp: "IMAGE                   TYPE     TAG     DIGEST      MANIFESTS  LAYERS  MISSING\n" +
+				"quay.io/foo/bar:latest  generic  latest  sha256:aaa  0          2       0\n",
+		},
+		{
+			name: "Valid/ManifestListWithMissingBlobs",
+			assocs: image.AssociationSet{
+				"quay.io/foo/list:v1": image.Associations{
+					"quay.io/foo/list:v1": {
+						Name:            "quay.io/foo/list:v1",
+						Path:            "foo/list",
+						ID:              "sha256:index",
+						TagSymlink:      "v1",
+						Type:            image.TypeOperatorBundle,
+						ManifestDigests: []string{"sha256:child"},
+					},
+					"sha256:child": {
+						Name:         "sha256:child",
+						Path:         "foo/list",
+						ID:           "sha256:child",
+						Type:         image.TypeOperatorBundle,
+						LayerDigests: []string{"sha256:l1", "sha256:l2"},
+					},
+				},
+			},
+			filesInArchive: map[string]string{
+				"sha256:l1": "mirror_seq1_00000.tar",
+			},
+			exp: "IMAGE                TYPE            TAG  DIGEST        MANIFESTS  LAYERS  MISSING\n" +
+				"quay.io/foo/list:v1  operatorBundle  v1   sha256:index  1          0       0\n" +
+				"  sha256:child       operatorBundle  -    sha256:child  0          2       1\n" +
+				"\nThe following images have blobs that are not in this imageset and must exist in the target registry:\n" +
+				"  quay.io/foo/list:v1\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeImages(&buf, c.assocs, c.filesInArchive))
+			require.Equal(t, c.exp, buf.String())
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/config"
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// sizeEstimate summarizes the estimated size of a planned imageset.
+// Blobs already present in a previous imageset are not counted.
+type sizeEstimate struct {
+	// Total is the size of all unique blobs in the imageset.
+	Total int64
+	// Images is the number of images sized.
+	Images int
+	// Splits is the number of archives the imageset will be split into.
+	Splits int
+	// SegmentSize is the maximum size of a single archive.
+	SegmentSize int64
+	// ByType contains the size of unique blobs per image type.
+	ByType map[image.ImageType]int64
+	// ByPackage contains the size of unique blobs per catalog and package.
+	ByPackage map[catalogPackage]int64
+}
+
+// catalogPackage identifies an operator package within a catalog.
+type catalogPackage struct {
+	Catalog string
+	Package string
+}
+
+// estimateSize aggregates image sizes by type and catalog package. Blobs
+// shared between images are counted once in each group they belong to.
+func estimateSize(sizes map[image.TypedImage]blobSizes, pastBlobs []v1alpha2.Blob, packages map[string]catalogPackage, segSize int64) sizeEstimate {
+	past := make(map[string]struct{}, len(pastBlobs))
+	for _, blob := range pastBlobs {
+		past[blob.ID] = struct{}{}
+	}
+
+	est := sizeEstimate{
+		Images:      len(sizes),
+		SegmentSize: segSize,
+		ByType:      map[image.ImageType]int64{},
+		ByPackage:   map[catalogPackage]int64{},
+	}
+	all := map[string]int64{}
+	seenByType := map[image.ImageType]map[string]struct{}{}
+	seenByPackage := map[catalogPackage]map[string]struct{}{}
+
+	addUnique := func(seen map[string]struct{}, digest string) bool {
+		if _, found := seen[digest]; found {
+			return false
+		}
+		seen[digest] = struct{}{}
+		return true
+	}
+
+	for img, imgSizes := range sizes {
+		if seenByType[img.Category] == nil {
+			seenByType[img.Category] = map[string]struct{}{}
+		}
+		pkg, hasPkg := packages[img.Ref.Exact()]
+		if hasPkg && seenByPackage[pkg] == nil {
+			seenByPackage[pkg] = map[string]struct{}{}
+		}
+		for digest, size := range imgSizes {
+			if _, found := past[digest]; found {
+				continue
+			}
+			all[digest] = size
+			if addUnique(seenByType[img.Category], digest) {
+				est.ByType[img.Category] += size
+			}
+			if hasPkg && addUnique(seenByPackage[pkg], digest) {
+				est.ByPackage[pkg] += size
+			}
+		}
+	}
+
+	// Archive blobs in the same order they are written to disk.
+	digests := make([]string, 0, len(all))
+	for digest, size := range all {
+		digests = append(digests, digest)
+		est.Total += size
+	}
+	sort.Strings(digests)
+	blobs := make([]int64, len(digests))
+	for i, digest := range digests {
+		blobs[i] = all[digest]
+	}
+	est.Splits = countSplits(blobs, segSize)
+
+	return est
+}
+
+// countSplits returns the number of archives created when writing
+// files with the given sizes into archives of at most maxSplitSize.
+func countSplits(sizes []int64, maxSplitSize int64) int {
+	splits := 1
+	var splitSize int64
+	for _, size := range sizes {
+		if splitSize != 0 && size+splitSize > maxSplitSize {
+			splits++
+			splitSize = 0
+		}
+		splitSize += size
+	}
+	return splits
+}
+
+// catalogPackages maps every bundle and related image in the file-based
+// catalogs under dir to the catalog and package that reference it.
+func catalogPackages(dir string) (map[string]catalogPackage, error) {
+	indexes, err := findCatalogIndexes(dir)
+	if err != nil {
+		return nil, err
+	}
+	packages := map[string]catalogPackage{}
+	for ctlgRef, indexDir := range indexes {
+		dc, err := declcfg.LoadFS(os.DirFS(indexDir))
+		if err != nil {
+			return nil, fmt.Errorf("error loading catalog %s: %v", ctlgRef.Ref.Exact(), err)
+		}
+		add := func(img, pkg string) error {
+			ref, err := imagesource.ParseReference(img)
+			if err != nil {
+				return err
+			}
+			packages[ref.Ref.Exact()] = catalogPackage{Catalog: ctlgRef.Ref.Exact(), Package: pkg}
+			return nil
+		}
+		for _, b := range dc.Bundles {
+			if err := add(b.Image, b.Package); err != nil {
+				return nil, err
+			}
+			for _, relatedImg := range b.RelatedImages {
+				if relatedImg.Image == "" {
+					continue
+				}
+				if err := add(relatedImg.Image, b.Package); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return packages, nil
+}
+
+// writeSizeEstimate prints est as a set of tables.
+func writeSizeEstimate(w io.Writer, est sizeEstimate) error {
+	if _, err := fmt.Fprintf(w, "Estimated imageset size: %s for %d images in %d archive(s) of at most %s\n",
+		formatBytes(uint64(est.Total)), est.Images, est.Splits, formatBytes(uint64(est.SegmentSize))); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, "Blobs shared between groups are counted in each group.\n\n"); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "TYPE\tSIZE"); err != nil {
+		return err
+	}
+	types := make([]image.ImageType, 0, len(est.ByType))
+	for typ := range est.ByType {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	for _, typ := range types {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", typ, formatBytes(uint64(est.ByType[typ]))); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(est.ByPackage) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	pkgs := make([]catalogPackage, 0, len(est.ByPackage))
+	for pkg := range est.ByPackage {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Catalog != pkgs[j].Catalog {
+			return pkgs[i].Catalog < pkgs[j].Catalog
+		}
+		return pkgs[i].Package < pkgs[j].Package
+	})
+	if _, err := fmt.Fprintln(tw, "CATALOG\tPACKAGE\tSIZE"); err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", pkg.Catalog, pkg.Package, formatBytes(uint64(est.ByPackage[pkg]))); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// writeDryRunEstimate fetches the manifests of all planned images
+// and prints the estimated size of the resulting imageset.
+func (o *MirrorOptions) writeDryRunEstimate(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata, mapping image.TypedImageMapping) error {
+	logrus.Info("Estimating imageset size")
+	sizes := o.imageSizes(ctx, cfg, mapping)
+	packages, err := catalogPackages(filepath.Join(o.Dir, config.SourceDir))
+	if err != nil {
+		return err
+	}
+	est := estimateSize(sizes, meta.PastBlobs, packages, segmentSize(cfg.ArchiveSize))
+	if err := writeSizeEstimate(o.IOStreams.Out, est); err != nil {
+		return err
+	}
+	return writeConditionalReleases(o.IOStreams.Out, meta.PastMirror.ConditionalReleases)
+}
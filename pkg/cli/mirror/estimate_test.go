@@ -0,0 +1,81 @@
+package mirror
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestEstimateSize(t *testing.T) {
+	release, err := image.ParseTypedImage("quay.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001", image.TypeOCPRelease)
+	require.NoError(t, err)
+	bundle, err := image.ParseTypedImage("quay.io/operator/bundle@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002", image.TypeOperatorBundle)
+	require.NoError(t, err)
+	related, err := image.ParseTypedImage("quay.io/operator/controller@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a003", image.TypeOperatorBundle)
+	require.NoError(t, err)
+
+	sizes := map[image.TypedImage]blobSizes{
+		release: {"sha256:m1": 10, "sha256:base": 100, "sha256:old": 1000},
+		bundle:  {"sha256:m2": 10, "sha256:b1": 20},
+		related: {"sha256:m3": 10, "sha256:base": 100, "sha256:c1": 200},
+	}
+	pastBlobs := []v1alpha2.Blob{{ID: "sha256:old"}}
+	pkg := catalogPackage{Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.10", Package: "foo"}
+	packages := map[string]catalogPackage{
+		bundle.Ref.Exact():  pkg,
+		related.Ref.Exact(): pkg,
+	}
+
+	est := estimateSize(sizes, pastBlobs, packages, 200)
+	exp := sizeEstimate{
+		Total:       350,
+		Images:      3,
+		Splits:      3,
+		SegmentSize: 200,
+		ByType: map[image.ImageType]int64{
+			image.TypeOCPRelease:     110,
+			image.TypeOperatorBundle: 340,
+		},
+		ByPackage: map[catalogPackage]int64{
+			pkg: 340,
+		},
+	}
+	require.Equal(t, exp, est)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeSizeEstimate(&buf, est))
+	require.Equal(t, "Estimated imageset size: 350 B for 3 images in 3 archive(s) of at most 200 B\n"+
+		"Blobs shared between groups are counted in each group.\n\n"+
+		"TYPE            SIZE\n"+
+		"ocpRelease      110 B\n"+
+		"operatorBundle  340 B\n"+
+		"\n"+
+		"CATALOG                                                PACKAGE  SIZE\n"+
+		"registry.redhat.io/redhat/redhat-operator-index:v4.10  foo      340 B\n", buf.String())
+}
+
+func TestCountSplits(t *testing.T) {
+	type spec struct {
+		name  string
+		sizes []int64
+		max   int64
+		exp   int
+	}
+
+	cases := []spec{
+		{name: "Valid/Empty", max: 10, exp: 1},
+		{name: "Valid/SingleSplit", sizes: []int64{2, 3, 5}, max: 10, exp: 1},
+		{name: "Valid/MultipleSplits", sizes: []int64{6, 4, 5, 5}, max: 10, exp: 2},
+		{name: "Valid/OversizedFile", sizes: []int64{20, 1}, max: 10, exp: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, countSplits(c.sizes, c.max))
+		})
+	}
+}
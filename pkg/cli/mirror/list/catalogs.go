@@ -0,0 +1,84 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	remoteerrors "github.com/containerd/containerd/remotes/errors"
+	imgreference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// defaultCatalogRegistry is the registry namespace the OperatorHub catalogs are published to.
+const defaultCatalogRegistry = "registry.redhat.io/redhat"
+
+// catalogIndexes are the repositories of the OperatorHub catalogs.
+var catalogIndexes = []string{
+	"redhat-operator-index",
+	"certified-operator-index",
+	"community-operator-index",
+	"redhat-marketplace-index",
+}
+
+// discoverCatalogs returns the OperatorHub catalogs published for the OpenShift
+// version to each registry namespace, with their resolved digests. Catalogs
+// without a tag for the version or that the user is not authorized to pull
+// are skipped.
+func discoverCatalogs(ctx context.Context, resolver remotes.Resolver, registries []string, version string) ([]Catalog, error) {
+	catalogs := []Catalog{}
+	for _, registry := range registries {
+		for _, index := range catalogIndexes {
+			ref := fmt.Sprintf("%s/%s:v%s", registry, index, version)
+			pin, err := image.ResolveToPin(ctx, resolver, ref)
+			switch {
+			case errdefs.IsNotFound(err):
+				logrus.Debugf("catalog %s not found, skipping", ref)
+				continue
+			case isAuthError(err):
+				logrus.Warnf("skipping catalog %s: %v", ref, err)
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("error resolving catalog %s: %v", ref, err)
+			}
+			pinned, err := imgreference.Parse(pin)
+			if err != nil {
+				return nil, err
+			}
+			catalogs = append(catalogs, Catalog{Name: index, Image: ref, Digest: pinned.ID})
+		}
+	}
+	if len(catalogs) == 0 {
+		return nil, fmt.Errorf("no catalogs found for OpenShift %s", version)
+	}
+	return catalogs, nil
+}
+
+// isAuthError returns true if err is an authentication or authorization
+// error returned by a registry, such as for a catalog requiring a subscription.
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr remoteerrors.ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden
+	}
+	if errors.Is(err, docker.ErrInvalidAuthorization) {
+		return true
+	}
+	// The resolver only reports the status of failed manifest requests in the error message.
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		if strings.Contains(err.Error(), fmt.Sprintf("%d %s", code, http.StatusText(code))) {
+			return true
+		}
+	}
+	return false
+}
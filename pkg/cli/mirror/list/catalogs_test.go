@@ -0,0 +1,96 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverCatalogs(t *testing.T) {
+	type spec struct {
+		name     string
+		version  string
+		exp      []Catalog
+		expError string
+	}
+
+	const digest = "sha256:d62495768e335c79a215ba56771ff5ae97e3cbb2bf49ed8fb3f6cefabcdc0f17"
+	manifests := map[string]int{
+		"/v2/redhat/redhat-operator-index/manifests/v4.9":     http.StatusOK,
+		"/v2/redhat/certified-operator-index/manifests/v4.9":  http.StatusUnauthorized,
+		"/v2/redhat/community-operator-index/manifests/v4.9":  http.StatusForbidden,
+		"/v2/redhat/redhat-marketplace-index/manifests/v4.9":  http.StatusOK,
+		"/v2/redhat/redhat-operator-index/manifests/v4.10":    http.StatusForbidden,
+		"/v2/redhat/redhat-marketplace-index/manifests/v4.10": http.StatusUnauthorized,
+		"/v2/redhat/redhat-operator-index/manifests/v4.11":    http.StatusInternalServerError,
+		"/v2/redhat/certified-operator-index/manifests/v4.11": http.StatusOK,
+		"/v2/redhat/community-operator-index/manifests/v4.11": http.StatusOK,
+		"/v2/redhat/redhat-marketplace-index/manifests/v4.11": http.StatusOK,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, found := manifests[r.URL.Path]
+		if !found || r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	registry := u.Host + "/redhat"
+
+	cases := []spec{
+		{
+			name:    "Valid/Discovered",
+			version: "4.9",
+			exp: []Catalog{
+				{Name: "redhat-operator-index", Image: registry + "/redhat-operator-index:v4.9", Digest: digest},
+				{Name: "redhat-marketplace-index", Image: registry + "/redhat-marketplace-index:v4.9", Digest: digest},
+			},
+		},
+		{
+			name:     "Invalid/NoCatalogs",
+			version:  "4.1",
+			expError: "no catalogs found for OpenShift 4.1",
+		},
+		{
+			name:     "Invalid/Unauthorized",
+			version:  "4.10",
+			expError: "no catalogs found for OpenShift 4.10",
+		},
+		{
+			name:    "Invalid/ServerError",
+			version: "4.11",
+			expError: fmt.Sprintf("error resolving catalog %s/redhat-operator-index:v4.11: pulling from host %s failed with status code "+
+				"[manifests v4.11]: 500 Internal Server Error", registry, u.Host),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resolver, err := containerdregistry.NewResolver("", false, true, nil)
+			require.NoError(t, err)
+			catalogs, err := discoverCatalogs(context.Background(), resolver, []string{registry}, c.version)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, catalogs)
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package list
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/operator-framework/operator-registry/alpha/action"
 	"github.com/operator-framework/operator-registry/alpha/model"
-	"github.com/sirupsen/logrus"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 	"github.com/spf13/cobra"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -22,6 +27,15 @@ type OperatorsOptions struct {
 	Channel  string
 	Version  string
 	Catalogs bool
+	Output   string
+	// CatalogRegistries are the registry namespaces catalogs are discovered in.
+	CatalogRegistries []string
+	// From is the path to an imageset to list catalogs from.
+	From string
+	// Search filters packages by name, description, and CSV keywords.
+	Search string
+	// Bundles lists the bundle images and related images of packages.
+	Bundles bool
 }
 
 func NewOperatorsCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
@@ -35,6 +49,9 @@ func NewOperatorsCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 			# Output default operator catalogs for OpenShift release 4.8
 			oc-mirror list operators --catalogs --version=4.8
 
+			# Output operator catalogs for OpenShift release 4.8 in a custom registry
+			oc-mirror list operators --catalogs --version=4.8 --catalog-registry=registry.example.com/redhat
+
 			# List all operator packages in a catalog
 			oc-mirror list operators --catalog=catalog-name
 
@@ -43,6 +60,18 @@ func NewOperatorsCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 
 			# List all available versions for a specified operator in a channel
 			oc-mirror list operators --catalog=catalog-name --package=operator-name --channel=channel-name
+
+			# List all operator packages in a catalog with their default channel and channel heads as YAML
+			oc-mirror list operators --catalog=catalog-name -o yaml
+
+			# List all operator packages in a file-based catalog directory or OCI layout matching a search term
+			oc-mirror list operators --catalog=/path/to/catalog --search=logging
+
+			# List all catalogs in an imageset
+			oc-mirror list operators --from=/path/to/archives
+
+			# List the bundle images and related images of an operator in a catalog in an imageset
+			oc-mirror list operators --from=/path/to/archives --catalog=catalog-name --package=operator-name --bundles
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete())
@@ -53,10 +82,16 @@ func NewOperatorsCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 
 	fs := cmd.Flags()
 	fs.BoolVar(&o.Catalogs, "catalogs", o.Catalogs, "List available catalogs for an OpenShift release version")
-	fs.StringVar(&o.Catalog, "catalog", o.Catalog, "List information for a specified catalog")
+	fs.StringVar(&o.Catalog, "catalog", o.Catalog, "List information for a specified catalog image, file-based catalog directory, or OCI layout")
 	fs.StringVar(&o.Package, "package", o.Package, "List information for a specified package")
 	fs.StringVar(&o.Channel, "channel", o.Channel, "List information for a specified channel")
 	fs.StringVar(&o.Version, "version", o.Version, "Specify an OpenShift release version")
+	fs.StringSliceVar(&o.CatalogRegistries, "catalog-registry", o.CatalogRegistries, "Registry namespace to discover catalogs in "+
+		"(can be specified multiple times, defaults to "+defaultCatalogRegistry+")")
+	fs.StringVar(&o.From, "from", o.From, "Path to an imageset archive or directory of archives to list catalogs from")
+	fs.StringVar(&o.Search, "search", o.Search, "List packages whose name, description, or keywords contain a search term")
+	fs.BoolVar(&o.Bundles, "bundles", o.Bundles, "List the bundle images and related images in a catalog")
+	bindOutputFlag(fs, &o.Output)
 
 	o.BindFlags(cmd.PersistentFlags())
 
@@ -80,7 +115,22 @@ func (o *OperatorsOptions) Validate() error {
 	if len(o.Package) > 0 && len(o.Catalog) == 0 {
 		return errors.New("must specify --catalog with --package")
 	}
-	return nil
+	if len(o.CatalogRegistries) > 0 && !o.Catalogs {
+		return errors.New("must specify --catalogs with --catalog-registry")
+	}
+	if len(o.From) > 0 && o.Catalogs {
+		return errors.New("cannot specify --catalogs with --from")
+	}
+	if len(o.Search) > 0 && len(o.Catalog) == 0 {
+		return errors.New("must specify --catalog with --search")
+	}
+	if len(o.Search) > 0 && len(o.Package) > 0 {
+		return errors.New("cannot specify --search with --package")
+	}
+	if o.Bundles && len(o.Catalog) == 0 {
+		return errors.New("must specify --catalog with --bundles")
+	}
+	return validateOutput(o.Output)
 }
 
 func (o *OperatorsOptions) Run(cmd *cobra.Command) error {
@@ -90,62 +140,142 @@ func (o *OperatorsOptions) Run(cmd *cobra.Command) error {
 
 	// Process cases from most specific to most broad
 	switch {
+	case len(o.From) > 0 && len(o.Catalog) == 0:
+		ctlgs, err := findImageSetCatalogs(o.From)
+		if err != nil {
+			return err
+		}
+		catalogs := make([]Catalog, 0, len(ctlgs))
+		for _, ctlg := range ctlgs {
+			catalogs = append(catalogs, Catalog{Name: ctlg.ref.Name, Image: ctlg.ref.Exact(), Digest: ctlg.ref.ID})
+		}
+		if isStructured(o.Output) {
+			return writeObject(w, o.Output, CatalogList{Catalogs: catalogs})
+		}
+		if _, err := fmt.Fprintf(w, "Catalogs in imageset %s:\n", o.From); err != nil {
+			return err
+		}
+		if err := writeCatalogs(w, catalogs); err != nil {
+			return err
+		}
+	case o.Bundles:
+		pkgs, err := o.loadPackages(ctx)
+		if err != nil {
+			return err
+		}
+		list := PackageList{Catalog: o.Catalog, Packages: make([]Package, 0, len(pkgs))}
+		for _, pkg := range pkgs {
+			p := newPackage(pkg)
+			p.Channels = []Channel{}
+			for _, ch := range pkg.Channels {
+				if len(o.Channel) > 0 && ch.Name != o.Channel {
+					continue
+				}
+				channel := newChannel(*ch)
+				channel.Bundles = newBundles(*ch, true)
+				p.Channels = append(p.Channels, channel)
+			}
+			if len(p.Channels) == 0 && len(o.Channel) > 0 {
+				return fmt.Errorf("channel %s not found in package %s", o.Channel, o.Package)
+			}
+			sort.Slice(p.Channels, func(i, j int) bool {
+				return p.Channels[i].Name < p.Channels[j].Name
+			})
+			list.Packages = append(list.Packages, p)
+		}
+		if isStructured(o.Output) {
+			return writeObject(w, o.Output, list)
+		}
+		if err := writeBundles(w, list); err != nil {
+			return err
+		}
 	case len(o.Channel) > 0:
 		// Print Version for all bundles in a channel
-		var ch model.Channel
-		lc := action.ListChannels{
-			IndexReference: o.Catalog,
-			PackageName:    o.Package,
-		}
-		res, err := lc.Run(ctx)
+		pkgs, err := o.loadPackages(ctx)
 		if err != nil {
-			logrus.Fatal(err)
+			return err
 		}
 		// Find target channel for searching
-		for _, c := range res.Channels {
-			if c.Name == o.Channel {
-				ch = c
-				break
-			}
+		ch, found := pkgs[0].Channels[o.Channel]
+		if !found {
+			return fmt.Errorf("channel %s not found in package %s", o.Channel, o.Package)
+		}
+
+		bundles := newBundles(*ch, false)
+		if isStructured(o.Output) {
+			pkg := newPackage(pkgs[0])
+			channel := newChannel(*ch)
+			channel.Bundles = bundles
+			pkg.Channels = []Channel{channel}
+			return writeObject(w, o.Output, PackageList{Catalog: o.Catalog, Packages: []Package{pkg}})
 		}
 
 		if _, err := fmt.Fprintln(w, "VERSIONS"); err != nil {
 			return err
 		}
 		// List all bundle versions in channel
-		for _, bndl := range ch.Bundles {
+		for _, bndl := range bundles {
 			if _, err := fmt.Fprintln(w, bndl.Version); err != nil {
 				return err
 			}
 		}
 	case len(o.Package) > 0:
-		lc := action.ListChannels{
-			IndexReference: o.Catalog,
-			PackageName:    o.Package,
-		}
-		res, err := lc.Run(ctx)
+		pkgs, err := o.loadPackages(ctx)
 		if err != nil {
-			logrus.Fatal(err)
+			return err
 		}
-		if err := res.WriteColumns(o.IOStreams.Out); err != nil {
-			logrus.Fatal(err)
+		if isStructured(o.Output) {
+			return writeObject(w, o.Output, PackageList{Catalog: o.Catalog, Packages: []Package{newPackage(pkgs[0])}})
 		}
-	case len(o.Catalog) > 0:
-		lp := action.ListPackages{
-			IndexReference: o.Catalog,
+		res := action.ListChannelsResult{}
+		for _, ch := range pkgs[0].Channels {
+			res.Channels = append(res.Channels, *ch)
+		}
+		sort.Slice(res.Channels, func(i, j int) bool {
+			return res.Channels[i].Name < res.Channels[j].Name
+		})
+		if err := res.WriteColumns(w); err != nil {
+			return err
 		}
-		res, err := lp.Run(ctx)
+	case len(o.Catalog) > 0:
+		pkgs, err := o.loadPackages(ctx)
 		if err != nil {
-			logrus.Fatal(err)
+			return err
+		}
+		if isStructured(o.Output) {
+			list := PackageList{Catalog: o.Catalog, Packages: make([]Package, 0, len(pkgs))}
+			for _, pkg := range pkgs {
+				list.Packages = append(list.Packages, newPackage(pkg))
+			}
+			return writeObject(w, o.Output, list)
 		}
-		if err := res.WriteColumns(o.IOStreams.Out); err != nil {
-			logrus.Fatal(err)
+		res := action.ListPackagesResult{Packages: pkgs}
+		if err := res.WriteColumns(w); err != nil {
+			return err
 		}
 	case o.Catalogs:
+		resolver, err := containerdregistry.NewResolver("", false, false, nil)
+		if err != nil {
+			return fmt.Errorf("error creating image resolver: %v", err)
+		}
+		registries := o.CatalogRegistries
+		if len(registries) == 0 {
+			registries = []string{defaultCatalogRegistry}
+		}
+		catalogs, err := discoverCatalogs(ctx, resolver, registries, o.Version)
+		if err != nil {
+			return err
+		}
+		if isStructured(o.Output) {
+			return writeObject(w, o.Output, CatalogList{Version: o.Version, Catalogs: catalogs})
+		}
 		if _, err := fmt.Fprintln(w, "Available OpenShift OperatorHub catalogs:"); err != nil {
 			return err
 		}
-		if err := o.writeIndexRef(w); err != nil {
+		if _, err := fmt.Fprintf(w, "OpenShift %s:\n", o.Version); err != nil {
+			return err
+		}
+		if err := writeCatalogs(w, catalogs); err != nil {
 			return err
 		}
 	default:
@@ -155,15 +285,98 @@ func (o *OperatorsOptions) Run(cmd *cobra.Command) error {
 	return nil
 }
 
-func (o *OperatorsOptions) writeIndexRef(w io.Writer) error {
-	catalogs := []string{"redhat", "certified", "community"}
-	if _, err := fmt.Fprintf(w, "OpenShift %s:\n", o.Version); err != nil {
+// loadPackages returns the packages in the catalog selected
+// by --package and --search, sorted by name.
+func (o *OperatorsOptions) loadPackages(ctx context.Context) ([]model.Package, error) {
+	m, err := o.loadCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Package) > 0 {
+		pkg, found := m[o.Package]
+		if !found {
+			return nil, fmt.Errorf("package %q not found", o.Package)
+		}
+		return []model.Package{*pkg}, nil
+	}
+	pkgs := []model.Package{}
+	for _, pkg := range m {
+		if len(o.Search) == 0 || matchesSearch(*pkg, o.Search) {
+			pkgs = append(pkgs, *pkg)
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return pkgs, nil
+}
+
+// matchesSearch returns true if the name, description, or the display name,
+// description, or keywords of the default channel head CSV of pkg contain term,
+// ignoring case.
+func matchesSearch(pkg model.Package, term string) bool {
+	fields := []string{pkg.Name, pkg.Description}
+	if pkg.DefaultChannel != nil {
+		if head, err := pkg.DefaultChannel.Head(); err == nil && head.CsvJSON != "" {
+			var csv struct {
+				Spec struct {
+					DisplayName string   `json:"displayName"`
+					Description string   `json:"description"`
+					Keywords    []string `json:"keywords"`
+				} `json:"spec"`
+			}
+			if err := json.Unmarshal([]byte(head.CsvJSON), &csv); err == nil {
+				fields = append(fields, csv.Spec.DisplayName, csv.Spec.Description)
+				fields = append(fields, csv.Spec.Keywords...)
+			}
+		}
+	}
+	term = strings.ToLower(term)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBundles writes the bundle images and related images of each package channel.
+func writeBundles(w io.Writer, list PackageList) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "PACKAGE\tCHANNEL\tBUNDLE\tIMAGE"); err != nil {
+		return err
+	}
+	for _, pkg := range list.Packages {
+		for _, ch := range pkg.Channels {
+			for _, b := range ch.Bundles {
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", pkg.Name, ch.Name, b.Name, b.Image); err != nil {
+					return err
+				}
+				for _, ri := range b.RelatedImages {
+					img := ri.Image
+					if len(ri.Name) > 0 {
+						img = fmt.Sprintf("%s (%s)", ri.Image, ri.Name)
+					}
+					if _, err := fmt.Fprintf(tw, "\t\t\t%s\n", img); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// writeCatalogs writes each catalog image and its digest.
+func writeCatalogs(w io.Writer, catalogs []Catalog) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "CATALOG\tDIGEST"); err != nil {
 		return err
 	}
 	for _, catalog := range catalogs {
-		if _, err := fmt.Fprintf(w, "registry.redhat.io/redhat/%s-operator-index:v%v\n", catalog, o.Version); err != nil {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", catalog.Image, catalog.Digest); err != nil {
 			return err
 		}
 	}
-	return nil
+	return tw.Flush()
 }
@@ -110,6 +110,79 @@ func TestOperatorsValidate(t *testing.T) {
 			},
 			expError: "",
 		},
+		{
+			name: "Invalid/CatalogRegistryWithoutCatalogs",
+			opts: &OperatorsOptions{
+				Catalog:           "foo-catalog",
+				CatalogRegistries: []string{"registry.example.com/redhat"},
+			},
+			expError: "must specify --catalogs with --catalog-registry",
+		},
+		{
+			name: "Valid/CatalogRegistry",
+			opts: &OperatorsOptions{
+				Catalogs:          true,
+				Version:           "4.8",
+				CatalogRegistries: []string{"registry.example.com/redhat"},
+			},
+			expError: "",
+		},
+		{
+			name: "Invalid/FromWithCatalogs",
+			opts: &OperatorsOptions{
+				Catalogs: true,
+				Version:  "4.8",
+				From:     "archives",
+			},
+			expError: "cannot specify --catalogs with --from",
+		},
+		{
+			name: "Invalid/SearchNoCatalog",
+			opts: &OperatorsOptions{
+				Search: "logging",
+			},
+			expError: "must specify --catalog with --search",
+		},
+		{
+			name: "Invalid/SearchWithPackage",
+			opts: &OperatorsOptions{
+				Catalog: "foo-catalog",
+				Package: "foo",
+				Search:  "logging",
+			},
+			expError: "cannot specify --search with --package",
+		},
+		{
+			name: "Invalid/BundlesNoCatalog",
+			opts: &OperatorsOptions{
+				Bundles: true,
+			},
+			expError: "must specify --catalog with --bundles",
+		},
+		{
+			name: "Valid/From",
+			opts: &OperatorsOptions{
+				From: "archives",
+			},
+			expError: "",
+		},
+		{
+			name: "Valid/SearchBundles",
+			opts: &OperatorsOptions{
+				Catalog: "foo-catalog",
+				Search:  "logging",
+				Bundles: true,
+			},
+			expError: "",
+		},
+		{
+			name: "Invalid/Output",
+			opts: &OperatorsOptions{
+				Catalog: "foo-catalog",
+				Output:  "xml",
+			},
+			expError: `--output must be 'table', 'json', or 'yaml', got "xml"`,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,238 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/oc-mirror/pkg/cincinnati"
+)
+
+// Output formats supported by the list commands.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// ChannelList is the structured output of listing the release channels of a version.
+type ChannelList struct {
+	Version  string   `json:"version"`
+	Channels []string `json:"channels"`
+}
+
+// ReleaseList is the structured output of listing the releases in a channel.
+type ReleaseList struct {
+	Channel            string              `json:"channel"`
+	Releases           []Release           `json:"releases"`
+	ConditionalUpdates []ConditionalUpdate `json:"conditionalUpdates,omitempty"`
+}
+
+// Release is a release version and its payload.
+type Release struct {
+	Version string `json:"version"`
+	Payload string `json:"payload"`
+	// Digest is empty if the payload is not referenced by digest.
+	Digest string `json:"digest,omitempty"`
+}
+
+// ConditionalUpdate is an update between two releases that is only recommended
+// for clusters not exposed to its risks.
+type ConditionalUpdate struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Risks []Risk `json:"risks"`
+}
+
+// Risk is a known issue of a conditional update.
+type Risk struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Message string `json:"message,omitempty"`
+}
+
+// CatalogList is the structured output of listing the catalogs of a version.
+type CatalogList struct {
+	// Version is empty when listing the catalogs in an imageset.
+	Version  string    `json:"version,omitempty"`
+	Catalogs []Catalog `json:"catalogs"`
+}
+
+// Catalog is an operator catalog image and its resolved digest.
+type Catalog struct {
+	Name   string `json:"name"`
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// PackageList is the structured output of listing the operator content of a catalog.
+type PackageList struct {
+	Catalog  string    `json:"catalog"`
+	Packages []Package `json:"packages"`
+}
+
+// Package is an operator package, its default channel, and its channels.
+type Package struct {
+	Name           string    `json:"name"`
+	DefaultChannel string    `json:"defaultChannel"`
+	Channels       []Channel `json:"channels"`
+}
+
+// Channel is a package channel and its head bundle. Bundles
+// are only listed when listing the versions in a channel.
+type Channel struct {
+	Name    string   `json:"name"`
+	Head    string   `json:"head"`
+	Bundles []Bundle `json:"bundles,omitempty"`
+}
+
+// Bundle is an operator bundle in a channel. Images are
+// only listed when listing bundle images.
+type Bundle struct {
+	Name          string         `json:"name"`
+	Version       string         `json:"version"`
+	Replaces      string         `json:"replaces,omitempty"`
+	Image         string         `json:"image,omitempty"`
+	RelatedImages []RelatedImage `json:"relatedImages,omitempty"`
+}
+
+// RelatedImage is an image referenced by an operator bundle.
+type RelatedImage struct {
+	Name  string `json:"name,omitempty"`
+	Image string `json:"image"`
+}
+
+// UpdateList is the structured output of listing the updates since the last mirror.
+type UpdateList struct {
+	Releases []ReleaseUpdates `json:"releases"`
+	Catalogs []CatalogUpdates `json:"catalogs"`
+}
+
+// ReleaseUpdates are the releases available in a channel since the last mirror.
+type ReleaseUpdates struct {
+	Channel  string    `json:"channel"`
+	Releases []Release `json:"releases"`
+}
+
+// CatalogUpdates are the bundles available in a catalog since the last mirror.
+type CatalogUpdates struct {
+	Catalog  string           `json:"catalog"`
+	Channels []ChannelUpdates `json:"channels"`
+}
+
+// ChannelUpdates are the bundles available in a package channel since the last mirror.
+type ChannelUpdates struct {
+	Package string   `json:"package"`
+	Channel string   `json:"channel"`
+	Bundles []Bundle `json:"bundles"`
+}
+
+// bindOutputFlag binds the output format flag of a list command to output.
+func bindOutputFlag(fs *pflag.FlagSet, output *string) {
+	fs.StringVarP(output, "output", "o", outputTable, "Output format. One of 'table', 'json', or 'yaml'")
+}
+
+// validateOutput returns an error if output is not a supported output format.
+// An empty output is the table format.
+func validateOutput(output string) error {
+	switch output {
+	case "", outputTable, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("--output must be 'table', 'json', or 'yaml', got %q", output)
+}
+
+// isStructured returns true if output is a structured output format.
+func isStructured(output string) bool {
+	return output == outputJSON || output == outputYAML
+}
+
+// writeObject writes obj to w in the structured output format.
+func writeObject(w io.Writer, output string, obj interface{}) error {
+	var data []byte
+	var err error
+	switch output {
+	case outputJSON:
+		data, err = json.MarshalIndent(obj, "", "  ")
+		data = append(data, '\n')
+	case outputYAML:
+		data, err = yaml.Marshal(obj)
+	default:
+		return fmt.Errorf("output format %q is not structured", output)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// newRelease returns the release of a Cincinnati update.
+func newRelease(update cincinnati.Update) Release {
+	release := Release{
+		Version: update.Version.String(),
+		Payload: update.Image,
+	}
+	if i := strings.LastIndex(update.Image, "@"); i != -1 {
+		release.Digest = update.Image[i+1:]
+	}
+	return release
+}
+
+// newPackage returns the package of a catalog model with its channels sorted by name.
+func newPackage(pkg model.Package) Package {
+	p := Package{Name: pkg.Name, Channels: []Channel{}}
+	if pkg.DefaultChannel != nil {
+		p.DefaultChannel = pkg.DefaultChannel.Name
+	}
+	for _, ch := range pkg.Channels {
+		p.Channels = append(p.Channels, newChannel(*ch))
+	}
+	sort.Slice(p.Channels, func(i, j int) bool {
+		return p.Channels[i].Name < p.Channels[j].Name
+	})
+	return p
+}
+
+// newChannel returns the channel of a catalog model without its bundles.
+// The head is empty if the channel does not have exactly one head.
+func newChannel(ch model.Channel) Channel {
+	c := Channel{Name: ch.Name}
+	if head, err := ch.Head(); err == nil {
+		c.Head = head.Name
+	}
+	return c
+}
+
+// newBundles returns the bundles of a catalog channel sorted by version,
+// with their bundle image and related images if images is true.
+func newBundles(ch model.Channel, images bool) []Bundle {
+	bundles := make([]*model.Bundle, 0, len(ch.Bundles))
+	for _, b := range ch.Bundles {
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		if !bundles[i].Version.EQ(bundles[j].Version) {
+			return bundles[i].Version.LT(bundles[j].Version)
+		}
+		return bundles[i].Name < bundles[j].Name
+	})
+	out := make([]Bundle, 0, len(bundles))
+	for _, b := range bundles {
+		bundle := Bundle{Name: b.Name, Version: b.Version.String(), Replaces: b.Replaces}
+		if images {
+			bundle.Image = b.Image
+			for _, ri := range b.RelatedImages {
+				bundle.RelatedImages = append(bundle.RelatedImages, RelatedImage{Name: ri.Name, Image: ri.Image})
+			}
+		}
+		out = append(out, bundle)
+	}
+	return out
+}
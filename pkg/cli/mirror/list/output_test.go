@@ -0,0 +1,108 @@
+package list
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/cincinnati"
+)
+
+func TestWriteObject(t *testing.T) {
+	type spec struct {
+		name     string
+		output   string
+		obj      interface{}
+		exp      string
+		expError string
+	}
+
+	list := ChannelList{Version: "4.9", Channels: []string{"fast-4.9", "stable-4.9"}}
+
+	cases := []spec{
+		{
+			name:   "Valid/JSON",
+			output: outputJSON,
+			obj:    list,
+			exp: `{
+  "version": "4.9",
+  "channels": [
+    "fast-4.9",
+    "stable-4.9"
+  ]
+}
+`,
+		},
+		{
+			name:   "Valid/YAML",
+			output: outputYAML,
+			obj:    list,
+			exp: `channels:
+- fast-4.9
+- stable-4.9
+version: "4.9"
+`,
+		},
+		{
+			name:     "Invalid/Table",
+			output:   outputTable,
+			obj:      list,
+			expError: `output format "table" is not structured`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeObject(&buf, c.output, c.obj)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, buf.String())
+			}
+		})
+	}
+}
+
+func TestNewRelease(t *testing.T) {
+	type spec struct {
+		name   string
+		update cincinnati.Update
+		exp    Release
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/Digest",
+			update: cincinnati.Update{
+				Version: semver.MustParse("4.9.0"),
+				Image:   "quay.io/openshift-release-dev/ocp-release@sha256:d62495768e335c79a215ba56771ff5ae97e3cbb2bf49ed8fb3f6cefabcdc0f17",
+			},
+			exp: Release{
+				Version: "4.9.0",
+				Payload: "quay.io/openshift-release-dev/ocp-release@sha256:d62495768e335c79a215ba56771ff5ae97e3cbb2bf49ed8fb3f6cefabcdc0f17",
+				Digest:  "sha256:d62495768e335c79a215ba56771ff5ae97e3cbb2bf49ed8fb3f6cefabcdc0f17",
+			},
+		},
+		{
+			name: "Valid/Tag",
+			update: cincinnati.Update{
+				Version: semver.MustParse("4.9.0"),
+				Image:   "quay.io/openshift-release-dev/ocp-release:4.9.0-x86_64",
+			},
+			exp: Release{
+				Version: "4.9.0",
+				Payload: "quay.io/openshift-release-dev/ocp-release:4.9.0-x86_64",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, newRelease(c.update))
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -20,6 +23,7 @@ type ReleasesOptions struct {
 	Channel  string
 	Channels bool
 	Version  string
+	Output   string
 }
 
 func NewReleasesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
@@ -38,6 +42,9 @@ func NewReleasesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 
 			# List all OCP channels for a specific version
 			oc-mirror list releases --channels --version=4.8
+
+			# List all OCP versions and their payloads in a specified channel as JSON
+			oc-mirror list releases --channel=stable-4.8 -o json
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete())
@@ -50,6 +57,7 @@ func NewReleasesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command
 	fs.StringVar(&o.Channel, "channel", o.Channel, "List information for a specified channel")
 	fs.BoolVar(&o.Channels, "channels", o.Channels, "List all channel information")
 	fs.StringVar(&o.Version, "version", o.Version, "Specify an OpenShift release version")
+	bindOutputFlag(fs, &o.Output)
 
 	o.BindFlags(cmd.PersistentFlags())
 
@@ -70,7 +78,7 @@ func (o *ReleasesOptions) Validate() error {
 	if o.Channel == "stable-" {
 		return errors.New("must specify --version or --channel")
 	}
-	return nil
+	return validateOutput(o.Output)
 }
 
 func (o *ReleasesOptions) Run(ctx context.Context) error {
@@ -78,17 +86,30 @@ func (o *ReleasesOptions) Run(ctx context.Context) error {
 	w := o.IOStreams.Out
 
 	client, err := cincinnati.NewOCPClient(uuid.New())
+	if err != nil {
+		return err
+	}
 
 	if o.Channels {
 		channels, err := cincinnati.GetChannels(ctx, client, o.Channel)
 		if err != nil {
 			return err
 		}
+		list := ChannelList{Version: o.Version, Channels: []string{}}
+		for channel := range channels {
+			if channel != "" {
+				list.Channels = append(list.Channels, channel)
+			}
+		}
+		sort.Strings(list.Channels)
 
+		if isStructured(o.Output) {
+			return writeObject(w, o.Output, list)
+		}
 		if _, err := fmt.Fprintf(w, "Listing channels for version %v.\n\n", o.Version); err != nil {
 			return err
 		}
-		for channel := range channels {
+		for _, channel := range list.Channels {
 			if _, err := fmt.Fprintf(w, "%s\n", channel); err != nil {
 				return err
 			}
@@ -96,6 +117,30 @@ func (o *ReleasesOptions) Run(ctx context.Context) error {
 		return nil
 	}
 
+	releases, err := cincinnati.GetReleases(ctx, client, "", o.Channel)
+	if err != nil {
+		return err
+	}
+	conditional, err := cincinnati.GetConditionalUpdates(ctx, client, "", o.Channel)
+	if err != nil {
+		return err
+	}
+
+	if isStructured(o.Output) {
+		list := ReleaseList{Channel: o.Channel, Releases: make([]Release, 0, len(releases))}
+		for _, release := range releases {
+			list.Releases = append(list.Releases, newRelease(release))
+		}
+		for _, update := range conditional {
+			cu := ConditionalUpdate{From: update.From.String(), To: update.To.String(), Risks: []Risk{}}
+			for _, risk := range update.Risks {
+				cu.Risks = append(cu.Risks, Risk{Name: risk.Name, URL: risk.URL, Message: risk.Message})
+			}
+			list.ConditionalUpdates = append(list.ConditionalUpdates, cu)
+		}
+		return writeObject(w, o.Output, list)
+	}
+
 	// By default, the stable channel versions will be listed
 	if strings.HasPrefix(o.Channel, "stable") {
 		if _, err := fmt.Fprintln(w, "Listing stable channels. Use --channel=<channel-name> to filter."); err != nil {
@@ -109,19 +154,36 @@ func (o *ReleasesOptions) Run(ctx context.Context) error {
 		}
 	}
 
-	vers, err := cincinnati.GetVersions(ctx, client, o.Channel)
-	if err != nil {
-		return err
-	}
-
 	if _, err := fmt.Fprintf(w, "Channel: %v\n", o.Channel); err != nil {
 		return err
 	}
-	for _, ver := range vers {
-		if _, err := fmt.Fprintf(w, "%s\n", ver); err != nil {
+	for _, release := range releases {
+		if _, err := fmt.Fprintf(w, "%s\n", release.Version); err != nil {
 			return err
 		}
 	}
+	return writeConditionalUpdates(w, conditional)
+}
 
-	return nil
+// writeConditionalUpdates writes the conditional updates
+// in a channel and the risks of each update.
+func writeConditionalUpdates(w io.Writer, updates []cincinnati.ConditionalUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "\nConditional updates:"); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "FROM\tTO\tRISK\tURL"); err != nil {
+		return err
+	}
+	for _, update := range updates {
+		for _, risk := range update.Risks {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", update.From, update.To, risk.Name, risk.URL); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
 }
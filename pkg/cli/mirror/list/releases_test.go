@@ -86,6 +86,14 @@ func TestReleasesValidate(t *testing.T) {
 			},
 			expError: "",
 		},
+		{
+			name: "Invalid/Output",
+			opts: &ReleasesOptions{
+				Channel: "stable-foo",
+				Output:  "xml",
+			},
+			expError: `--output must be 'table', 'json', or 'yaml', got "xml"`,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,326 @@
+package list
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/mholt/archiver/v3"
+	imgreference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/operator-framework/operator-registry/alpha/action"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+
+	"github.com/openshift/oc-mirror/pkg/archive"
+)
+
+// loadCatalog returns the model of the catalog in an imageset, an OCI layout,
+// a file-based catalog directory, or a catalog image.
+func (o *OperatorsOptions) loadCatalog(ctx context.Context) (model.Model, error) {
+	if len(o.From) != 0 {
+		return loadImageSetCatalog(o.From, o.Catalog)
+	}
+	if _, err := os.Stat(filepath.Join(o.Catalog, "oci-layout")); err == nil {
+		return loadLayoutCatalog(o.Catalog)
+	}
+	// Render file-based catalog directories and images the same way the opm list commands do.
+	render := action.Render{
+		Refs:           []string{o.Catalog},
+		AllowedRefMask: action.RefDCImage | action.RefDCDir | action.RefSqliteImage | action.RefSqliteFile,
+	}
+	dc, err := render.Run(ctx)
+	if err != nil {
+		if errors.Is(err, action.ErrNotAllowed) {
+			return nil, fmt.Errorf("cannot list non-index %q", o.Catalog)
+		}
+		return nil, err
+	}
+	return declcfg.ConvertToModel(*dc)
+}
+
+// imageSetCatalog is a file-based catalog packed in an imageset archive.
+type imageSetCatalog struct {
+	// archive is the path of the archive containing the catalog.
+	archive string
+	// file is the path of the catalog in the archive.
+	file string
+	// ref is the source catalog image.
+	ref imgreference.DockerImageReference
+}
+
+// imageSetSeqRe matches the name of an imageset archive and captures its sequence number.
+var imageSetSeqRe = regexp.MustCompile(`^mirror_seq([0-9]+)_[0-9]+\.`)
+
+// imageSetSequence returns the sequence number of the imageset
+// archive at arc, or 0 if the archive is not named by sequence.
+func imageSetSequence(arc string) int {
+	match := imageSetSeqRe.FindStringSubmatch(filepath.Base(arc))
+	if match == nil {
+		return 0
+	}
+	seq, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+// imageSetArchives returns the imageset archives at from, which is
+// either an archive or a directory containing archives.
+func imageSetArchives(a archive.Archiver, from string) ([]string, error) {
+	info, err := os.Stat(from)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{from}, nil
+	}
+	var archives []string
+	err = filepath.Walk(from, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("traversing %s: %v", fpath, err)
+		}
+		if !info.IsDir() && strings.TrimPrefix(filepath.Ext(fpath), ".") == a.String() {
+			archives = append(archives, fpath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("no archives found in directory %s", from)
+	}
+	return archives, nil
+}
+
+// findImageSetCatalogs returns the file-based catalogs packed in the imageset
+// archives at from, sorted by catalog image. A catalog packed in more than one
+// archive is read from the archive with the highest sequence number, since
+// each imageset contains the full catalog as of its creation.
+func findImageSetCatalogs(from string) ([]imageSetCatalog, error) {
+	a := archive.NewArchiver()
+	archives, err := imageSetArchives(a, from)
+	if err != nil {
+		return nil, err
+	}
+
+	byRef := map[string]imageSetCatalog{}
+	for _, arc := range archives {
+		err := a.Walk(arc, func(f archiver.File) error {
+			header, ok := f.Header.(*tar.Header)
+			if !ok {
+				return fmt.Errorf("expected header to be *tar.Header but was %T", f.Header)
+			}
+			if f.IsDir() || !strings.HasPrefix(header.Name, "catalogs/") || path.Base(header.Name) != "index.json" {
+				return nil
+			}
+			// Catalogs are packed at catalogs/<registry>/<namespace>/<name>/<tag or digest>/index.json.
+			repo, id := path.Split(path.Dir(strings.TrimPrefix(header.Name, "catalogs/")))
+			img := fmt.Sprintf("%s:%s", path.Clean(repo), id)
+			if strings.Contains(id, ":") {
+				img = fmt.Sprintf("%s@%s", path.Clean(repo), id)
+			}
+			ref, err := imgreference.Parse(img)
+			if err != nil {
+				return fmt.Errorf("error parsing catalog path %q as image %q: %v", header.Name, img, err)
+			}
+			ctlg := imageSetCatalog{archive: arc, file: header.Name, ref: ref}
+			if found, ok := byRef[ref.Exact()]; ok && !newerArchive(ctlg.archive, found.archive) {
+				return nil
+			}
+			byRef[ref.Exact()] = ctlg
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	catalogs := make([]imageSetCatalog, 0, len(byRef))
+	for _, ctlg := range byRef {
+		catalogs = append(catalogs, ctlg)
+	}
+	sort.Slice(catalogs, func(i, j int) bool {
+		return catalogs[i].ref.Exact() < catalogs[j].ref.Exact()
+	})
+	return catalogs, nil
+}
+
+// newerArchive returns true if the imageset archive at arc has a higher sequence
+// number than other. Archives with the same sequence are ordered by path.
+func newerArchive(arc, other string) bool {
+	seq, otherSeq := imageSetSequence(arc), imageSetSequence(other)
+	if seq != otherSeq {
+		return seq > otherSeq
+	}
+	return arc > other
+}
+
+// loadImageSetCatalog returns the model of the catalog image
+// packed in the imageset archives at from.
+func loadImageSetCatalog(from, catalog string) (model.Model, error) {
+	ref, err := imgreference.Parse(catalog)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing catalog %q: %v", catalog, err)
+	}
+	catalogs, err := findImageSetCatalogs(from)
+	if err != nil {
+		return nil, err
+	}
+	for _, ctlg := range catalogs {
+		if ctlg.ref.Exact() != ref.Exact() {
+			continue
+		}
+		var dc *declcfg.DeclarativeConfig
+		err := archive.NewArchiver().Walk(ctlg.archive, func(f archiver.File) error {
+			header, ok := f.Header.(*tar.Header)
+			if !ok || header.Name != ctlg.file {
+				return nil
+			}
+			var lerr error
+			if dc, lerr = loadCatalogFile(f); lerr != nil {
+				return lerr
+			}
+			return archiver.ErrStopWalk
+		})
+		if err != nil {
+			return nil, err
+		}
+		if dc == nil {
+			return nil, fmt.Errorf("catalog %s not found in archive %s", catalog, ctlg.archive)
+		}
+		return declcfg.ConvertToModel(*dc)
+	}
+	return nil, fmt.Errorf("catalog %s not found in imageset %s", catalog, from)
+}
+
+// loadCatalogFile returns the declarative config in a file-based catalog file.
+func loadCatalogFile(r io.Reader) (*declcfg.DeclarativeConfig, error) {
+	dir, err := os.MkdirTemp("", "catalog-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	f, err := os.Create(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return declcfg.LoadFS(os.DirFS(dir))
+}
+
+// loadLayoutCatalog returns the model of the catalog image in the OCI layout at dir.
+// The first image in the layout is used, since catalog content does not vary by platform.
+func loadLayoutCatalog(dir string) (model.Model, error) {
+	idx, err := layout.ImageIndexFromPath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OCI layout %s: %v", dir, err)
+	}
+	img, err := firstImage(idx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OCI layout %s: %v", dir, err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	configsDir, ok := cfg.Config.Labels[containertools.ConfigsLocationLabel]
+	if !ok {
+		return nil, fmt.Errorf("image in OCI layout %s is not a file-based catalog: missing label %s", dir, containertools.ConfigsLocationLabel)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "catalog-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractConfigs(img, configsDir, tmpDir); err != nil {
+		return nil, fmt.Errorf("error extracting catalog from OCI layout %s: %v", dir, err)
+	}
+	dc, err := declcfg.LoadFS(os.DirFS(tmpDir))
+	if err != nil {
+		return nil, err
+	}
+	return declcfg.ConvertToModel(*dc)
+}
+
+// firstImage returns the first image in idx, descending into nested indexes.
+func firstImage(idx v1.ImageIndex) (v1.Image, error) {
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range manifest.Manifests {
+		switch {
+		case desc.MediaType.IsImage():
+			return idx.Image(desc.Digest)
+		case desc.MediaType.IsIndex():
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			return firstImage(child)
+		}
+	}
+	return nil, errors.New("no images found")
+}
+
+// extractConfigs extracts the regular files under configsDir
+// in the flattened filesystem of img to dst.
+func extractConfigs(img v1.Image, configsDir, dst string) error {
+	prefix := strings.Trim(path.Clean(configsDir), "/") + "/"
+	if prefix == "/" {
+		prefix = ""
+	}
+	rc := mutate.Extract(img)
+	defer rc.Close()
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if header.Typeflag != tar.TypeReg || !strings.HasPrefix(name, prefix) || strings.HasPrefix(name, "../") {
+			continue
+		}
+		target := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(name, prefix)))
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
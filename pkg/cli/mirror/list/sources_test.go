@@ -0,0 +1,257 @@
+package list
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPackages(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *OperatorsOptions
+		exp      []string
+		expError string
+	}
+
+	index := writeTestCatalog(t)
+	dir := filepath.Dir(index)
+	layoutDir := writeTestLayout(t, index)
+	imageSet := writeTestImageSet(t, filepath.Join(t.TempDir(), "mirror_seq1_000000.tar"), index, "catalogs/registry.example.com/ns/catalog/v1/index.json")
+
+	cases := []spec{
+		{
+			name: "Valid/Directory",
+			opts: &OperatorsOptions{Catalog: dir},
+			exp:  []string{"bar", "foo"},
+		},
+		{
+			name: "Valid/Package",
+			opts: &OperatorsOptions{Catalog: dir, Package: "foo"},
+			exp:  []string{"foo"},
+		},
+		{
+			name: "Valid/SearchKeywords",
+			opts: &OperatorsOptions{Catalog: dir, Search: "LOGGING"},
+			exp:  []string{"bar"},
+		},
+		{
+			name: "Valid/SearchDescription",
+			opts: &OperatorsOptions{Catalog: dir, Search: "manages foo"},
+			exp:  []string{"foo"},
+		},
+		{
+			name: "Valid/SearchNoMatch",
+			opts: &OperatorsOptions{Catalog: dir, Search: "baz"},
+			exp:  []string{},
+		},
+		{
+			name: "Valid/Layout",
+			opts: &OperatorsOptions{Catalog: layoutDir},
+			exp:  []string{"bar", "foo"},
+		},
+		{
+			name: "Valid/ImageSet",
+			opts: &OperatorsOptions{From: imageSet, Catalog: "registry.example.com/ns/catalog:v1"},
+			exp:  []string{"bar", "foo"},
+		},
+		{
+			name:     "Invalid/PackageNotFound",
+			opts:     &OperatorsOptions{Catalog: dir, Package: "baz"},
+			expError: `package "baz" not found`,
+		},
+		{
+			name:     "Invalid/ImageSetCatalogNotFound",
+			opts:     &OperatorsOptions{From: imageSet, Catalog: "registry.example.com/ns/catalog:v2"},
+			expError: "catalog registry.example.com/ns/catalog:v2 not found in imageset " + imageSet,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pkgs, err := c.opts.loadPackages(context.Background())
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				names := []string{}
+				for _, pkg := range pkgs {
+					names = append(names, pkg.Name)
+				}
+				require.Equal(t, c.exp, names)
+			}
+		})
+	}
+}
+
+func TestFindImageSetCatalogs(t *testing.T) {
+	index := writeTestCatalog(t)
+	digest := "sha256:d62495768e335c79a215ba56771ff5ae97e3cbb2bf49ed8fb3f6cefabcdc0f17"
+	imageSet := writeTestImageSet(t, filepath.Join(t.TempDir(), "mirror_seq1_000000.tar"), index,
+		"catalogs/registry.example.com/ns/catalog/v1/index.json",
+		"catalogs/registry.example.com/ns/other/"+digest+"/index.json",
+	)
+
+	catalogs, err := findImageSetCatalogs(imageSet)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 2)
+	require.Equal(t, "registry.example.com/ns/catalog:v1", catalogs[0].ref.Exact())
+	require.Equal(t, "registry.example.com/ns/other@"+digest, catalogs[1].ref.Exact())
+	require.Equal(t, digest, catalogs[1].ref.ID)
+}
+
+func TestFindImageSetCatalogsLatestArchive(t *testing.T) {
+	name := "catalogs/registry.example.com/ns/catalog/v1/index.json"
+	stale := filepath.Join(t.TempDir(), "index.json")
+	f, err := os.Create(stale)
+	require.NoError(t, err)
+	require.NoError(t, declcfg.WriteJSON(declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{{Schema: "olm.package", Name: "baz", DefaultChannel: "stable"}},
+		Channels: []declcfg.Channel{{Schema: "olm.channel", Name: "stable", Package: "baz", Entries: []declcfg.ChannelEntry{{Name: "baz.v0.1.0"}}}},
+		Bundles: []declcfg.Bundle{{
+			Schema:     "olm.bundle",
+			Name:       "baz.v0.1.0",
+			Package:    "baz",
+			Image:      "registry.example.com/baz-bundle:v0.1.0",
+			Properties: []property.Property{property.MustBuildPackage("baz", "0.1.0")},
+		}},
+	}, f))
+	require.NoError(t, f.Close())
+
+	// The latest archive sorts before the stale archive by name.
+	dir := t.TempDir()
+	latest := writeTestImageSet(t, filepath.Join(dir, "mirror_seq10_000000.tar"), writeTestCatalog(t), name)
+	writeTestImageSet(t, filepath.Join(dir, "mirror_seq2_000000.tar"), stale, name)
+
+	catalogs, err := findImageSetCatalogs(dir)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	require.Equal(t, "registry.example.com/ns/catalog:v1", catalogs[0].ref.Exact())
+	require.Equal(t, latest, catalogs[0].archive)
+
+	m, err := loadImageSetCatalog(dir, "registry.example.com/ns/catalog:v1")
+	require.NoError(t, err)
+	require.Contains(t, m, "foo")
+	require.Contains(t, m, "bar")
+	require.NotContains(t, m, "baz")
+}
+
+func TestNewBundlesImages(t *testing.T) {
+	index := writeTestCatalog(t)
+	pkgs, err := (&OperatorsOptions{Catalog: filepath.Dir(index), Package: "foo"}).loadPackages(context.Background())
+	require.NoError(t, err)
+
+	exp := []Bundle{
+		{
+			Name:          "foo.v0.1.0",
+			Version:       "0.1.0",
+			Image:         "registry.example.com/foo-bundle:v0.1.0",
+			RelatedImages: []RelatedImage{{Name: "operator", Image: "registry.example.com/foo:v0.1.0"}},
+		},
+	}
+	require.Equal(t, exp, newBundles(*pkgs[0].Channels["stable"], true))
+	require.Equal(t, []Bundle{{Name: "foo.v0.1.0", Version: "0.1.0"}}, newBundles(*pkgs[0].Channels["stable"], false))
+}
+
+// writeTestCatalog writes a file-based catalog with the
+// packages foo and bar and returns the path of the catalog file.
+func writeTestCatalog(t *testing.T) string {
+	csv := []byte(`{"apiVersion":"operators.coreos.com/v1alpha1","kind":"ClusterServiceVersion","metadata":{"name":"bar.v0.1.0"},"spec":{"displayName":"Bar","keywords":["logging"]}}`)
+	dc := declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{
+			{Schema: "olm.package", Name: "foo", DefaultChannel: "stable", Description: "Manages foo"},
+			{Schema: "olm.package", Name: "bar", DefaultChannel: "stable"},
+		},
+		Channels: []declcfg.Channel{
+			{Schema: "olm.channel", Name: "stable", Package: "foo", Entries: []declcfg.ChannelEntry{{Name: "foo.v0.1.0"}}},
+			{Schema: "olm.channel", Name: "stable", Package: "bar", Entries: []declcfg.ChannelEntry{{Name: "bar.v0.1.0"}}},
+		},
+		Bundles: []declcfg.Bundle{
+			{
+				Schema:        "olm.bundle",
+				Name:          "foo.v0.1.0",
+				Package:       "foo",
+				Image:         "registry.example.com/foo-bundle:v0.1.0",
+				Properties:    []property.Property{property.MustBuildPackage("foo", "0.1.0")},
+				RelatedImages: []declcfg.RelatedImage{{Name: "operator", Image: "registry.example.com/foo:v0.1.0"}},
+			},
+			{
+				Schema:  "olm.bundle",
+				Name:    "bar.v0.1.0",
+				Package: "bar",
+				Image:   "registry.example.com/bar-bundle:v0.1.0",
+				Properties: []property.Property{
+					property.MustBuildPackage("bar", "0.1.0"),
+					property.MustBuildBundleObjectData(csv),
+				},
+			},
+		},
+	}
+	index := filepath.Join(t.TempDir(), "index.json")
+	f, err := os.Create(index)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, declcfg.WriteJSON(dc, f))
+	return index
+}
+
+// writeTestLayout writes an OCI layout containing a catalog
+// image with the catalog file index and returns its path.
+func writeTestLayout(t *testing.T, index string) string {
+	data, err := ioutil.ReadFile(index)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "configs/index.json", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))}))
+	_, err = tw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	require.NoError(t, err)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+	img, err = mutate.Config(img, v1.Config{Labels: map[string]string{containertools.ConfigsLocationLabel: "/configs"}})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	p, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendImage(img))
+	return dir
+}
+
+// writeTestImageSet writes an imageset archive at path containing
+// the catalog file index at each name and returns its path.
+func writeTestImageSet(t *testing.T, path, index string, names ...string) string {
+	data, err := ioutil.ReadFile(index)
+	require.NoError(t, err)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))}))
+		_, err = tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return path
+}
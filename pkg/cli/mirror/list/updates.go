@@ -6,13 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"text/tabwriter"
 
 	"github.com/blang/semver/v4"
 	"github.com/google/uuid"
 	"github.com/operator-framework/operator-registry/alpha/action"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
-	"github.com/operator-framework/operator-registry/alpha/model"
 	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -29,6 +29,7 @@ import (
 type UpdatesOptions struct {
 	*cli.RootOptions
 	ConfigPath string
+	Output     string
 }
 
 func NewUpdatesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
@@ -45,6 +46,9 @@ func NewUpdatesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
 		Example: templates.Examples(`
 			# List updates between remote and current workspace
 			oc-mirror list updates --config mirror-config.yaml
+
+			# List updates between remote and current workspace as JSON
+			oc-mirror list updates --config mirror-config.yaml -o json
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Validate())
@@ -56,6 +60,7 @@ func NewUpdatesCommand(f kcmdutil.Factory, ro *cli.RootOptions) *cobra.Command {
 
 	fs := cmd.Flags()
 	fs.StringVarP(&o.ConfigPath, "config", "c", o.ConfigPath, "Path to imageset configuration file")
+	bindOutputFlag(fs, &o.Output)
 	return cmd
 }
 
@@ -63,7 +68,7 @@ func (o *UpdatesOptions) Validate() error {
 	if len(o.ConfigPath) == 0 {
 		return fmt.Errorf("must specify config using --config")
 	}
-	return nil
+	return validateOutput(o.Output)
 }
 
 func (o *UpdatesOptions) Run(ctx context.Context) error {
@@ -84,29 +89,43 @@ func (o *UpdatesOptions) Run(ctx context.Context) error {
 		return err
 	case err != nil && errors.Is(err, storage.ErrMetadataNotExist):
 		return fmt.Errorf("no metadata detected")
-	default:
-		if len(cfg.Mirror.OCP.Channels) != 0 {
-			if err := o.releaseUpdates(ctx, "amd64", cfg, meta.PastMirror); err != nil {
-				return err
-			}
+	}
+
+	updates := UpdateList{Releases: []ReleaseUpdates{}, Catalogs: []CatalogUpdates{}}
+	if len(cfg.Mirror.OCP.Channels) != 0 {
+		if updates.Releases, err = o.releaseUpdates(ctx, "amd64", cfg, meta.PastMirror); err != nil {
+			return err
 		}
-		if len(cfg.Mirror.Operators) != 0 {
-			if err := o.operatorUpdates(ctx, cfg, meta); err != nil {
-				return err
-			}
+	}
+	if len(cfg.Mirror.Operators) != 0 {
+		if updates.Catalogs, err = o.operatorUpdates(ctx, cfg, meta); err != nil {
+			return err
 		}
 	}
 
+	if isStructured(o.Output) {
+		return writeObject(o.IOStreams.Out, o.Output, updates)
+	}
+	for _, ru := range updates.Releases {
+		if err := o.writeReleaseColumns(ru); err != nil {
+			return err
+		}
+	}
+	for _, cu := range updates.Catalogs {
+		if err := o.writeCatalogColumns(cu); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (o UpdatesOptions) releaseUpdates(ctx context.Context, arch string, cfg v1alpha2.ImageSetConfiguration, last v1alpha2.PastMirror) error {
+func (o UpdatesOptions) releaseUpdates(ctx context.Context, arch string, cfg v1alpha2.ImageSetConfiguration, last v1alpha2.PastMirror) ([]ReleaseUpdates, error) {
 	logrus.Info("Getting release update information")
 	lastMaxVersion := map[string]semver.Version{}
 	for _, ch := range last.Mirror.OCP.Channels {
 		version, err := semver.Parse(ch.MaxVersion)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		lastMaxVersion[ch.Name] = version
 	}
@@ -115,6 +134,7 @@ func (o UpdatesOptions) releaseUpdates(ctx context.Context, arch string, cfg v1a
 	// versions if available
 	id := uuid.New()
 
+	var updates []ReleaseUpdates
 	for _, ch := range cfg.Mirror.OCP.Channels {
 
 		var c cincinnati.Client
@@ -125,38 +145,35 @@ func (o UpdatesOptions) releaseUpdates(ctx context.Context, arch string, cfg v1a
 			c, err = cincinnati.NewOCPClient(id)
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		latest, err := cincinnati.GetChannelMinOrMax(ctx, c, arch, ch.Name, false)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ver, found := lastMaxVersion[ch.Name]
 		if !found {
 			ver = latest
 		}
-		_, _, upgrades, err := cincinnati.GetUpdates(ctx, c, arch, ch.Name, ver, latest)
+		_, _, upgrades, err := cincinnati.GetUpdates(ctx, c, arch, ch.Name, ver, latest, cincinnati.RiskPolicy(ch))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		var vers []semver.Version
+		ru := ReleaseUpdates{Channel: ch.Name, Releases: make([]Release, 0, len(upgrades))}
 		for _, upgrade := range upgrades {
-			vers = append(vers, upgrade.Version)
-		}
-
-		if err := o.writeReleaseColumns(vers, ch.Name); err != nil {
-			return err
+			ru.Releases = append(ru.Releases, newRelease(upgrade))
 		}
+		updates = append(updates, ru)
 	}
-	return nil
+	return updates, nil
 }
 
-func (o UpdatesOptions) operatorUpdates(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata) error {
+func (o UpdatesOptions) operatorUpdates(ctx context.Context, cfg v1alpha2.ImageSetConfiguration, meta v1alpha2.Metadata) ([]CatalogUpdates, error) {
 	logrus.Info("Getting operator update information")
 	dstDir, err := os.MkdirTemp(o.Dir, "updatetmp-")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer os.RemoveAll(dstDir)
 
@@ -174,15 +191,17 @@ func (o UpdatesOptions) operatorUpdates(ctx context.Context, cfg v1alpha2.ImageS
 		containerdregistry.SkipTLSVerify(false),
 		containerdregistry.WithCacheDir(filepath.Join(dstDir, "cache")),
 	)
-	defer reg.Destroy()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer reg.Destroy()
+
+	var updates []CatalogUpdates
 	for _, ctlg := range cfg.Mirror.Operators {
 		catLogger := logrus.WithField("catalog", ctlg.Catalog)
 		dic, err := ctlg.IncludeConfig.ConvertToDiffIncludeConfig()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		diff := action.Diff{
 			Registry:      reg,
@@ -195,76 +214,86 @@ func (o UpdatesOptions) operatorUpdates(ctx context.Context, cfg v1alpha2.ImageS
 		}
 		dc, err := diff.Run(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if err := o.writeCatalogColumns(*dc, ctlg.Catalog); err != nil {
-			return err
+		cu, err := catalogUpdates(*dc, ctlg.Catalog)
+		if err != nil {
+			return nil, err
 		}
+		updates = append(updates, cu)
 	}
-	return nil
+	return updates, nil
+}
+
+// catalogUpdates returns the bundles in the diff of a catalog
+// by package and channel, sorted by package and channel name.
+func catalogUpdates(dc declcfg.DeclarativeConfig, catalog string) (CatalogUpdates, error) {
+	cu := CatalogUpdates{Catalog: catalog, Channels: []ChannelUpdates{}}
+	mod, err := declcfg.ConvertToModel(dc)
+	if err != nil {
+		return cu, err
+	}
+	for _, pkg := range mod {
+		for _, ch := range pkg.Channels {
+			cu.Channels = append(cu.Channels, ChannelUpdates{
+				Package: pkg.Name,
+				Channel: ch.Name,
+				Bundles: newBundles(*ch, false),
+			})
+		}
+	}
+	sort.Slice(cu.Channels, func(i, j int) bool {
+		if cu.Channels[i].Package != cu.Channels[j].Package {
+			return cu.Channels[i].Package < cu.Channels[j].Package
+		}
+		return cu.Channels[i].Channel < cu.Channels[j].Channel
+	})
+	return cu, nil
 }
 
-func (o UpdatesOptions) writeReleaseColumns(upgrades []semver.Version, channel string) error {
-	if len(upgrades) == 0 {
-		if _, err := fmt.Fprintf(os.Stdout, "No updates found for release channel %s\n", channel); err != nil {
+func (o UpdatesOptions) writeReleaseColumns(updates ReleaseUpdates) error {
+	if len(updates.Releases) == 0 {
+		if _, err := fmt.Fprintf(o.IOStreams.Out, "No updates found for release channel %s\n", updates.Channel); err != nil {
 			return err
 		}
 		return nil
 	}
 	tw := tabwriter.NewWriter(o.IOStreams.Out, 0, 4, 2, ' ', 0)
-	if _, err := fmt.Fprintf(tw, "TARGET CHANNEL:\t%s\n", channel); err != nil {
+	if _, err := fmt.Fprintf(tw, "TARGET CHANNEL:\t%s\n", updates.Channel); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintln(tw, "VERSIONS"); err != nil {
 		return err
 	}
-	for _, upgrade := range upgrades {
-		if _, err := fmt.Fprintf(tw, "%s\n", upgrade); err != nil {
+	for _, release := range updates.Releases {
+		if _, err := fmt.Fprintf(tw, "%s\n", release.Version); err != nil {
 			return err
 		}
 	}
 	return tw.Flush()
 }
 
-func (o UpdatesOptions) writeCatalogColumns(dc declcfg.DeclarativeConfig, catalog string) error {
-	if len(dc.Packages) == 0 {
-		if _, err := fmt.Fprintf(os.Stdout, "No updates found for catalog %s\n", catalog); err != nil {
+func (o UpdatesOptions) writeCatalogColumns(updates CatalogUpdates) error {
+	if len(updates.Channels) == 0 {
+		if _, err := fmt.Fprintf(o.IOStreams.Out, "No updates found for catalog %s\n", updates.Catalog); err != nil {
 			return err
 		}
 		return nil
 	}
-	tw := tabwriter.NewWriter(o.IOStreams.Out, 0, 4, 2, ' ', 0)
-	if _, err := fmt.Fprintf(tw, "Listing update for catalog:\t%s", catalog); err != nil {
+	if _, err := fmt.Fprintf(o.IOStreams.Out, "Listing update for catalog: %s\n", updates.Catalog); err != nil {
 		return err
 	}
+	tw := tabwriter.NewWriter(o.IOStreams.Out, 0, 4, 2, ' ', 0)
 	if _, err := fmt.Fprintln(tw, "PACKAGE\tCHANNEL\tBUNDLE\tREPLACES"); err != nil {
 		return err
 	}
-	mod, err := declcfg.ConvertToModel(dc)
-	if err != nil {
-		return err
-	}
-
-	pkgs := []model.Package{}
-	for _, pkg := range mod {
-		pkgs = append(pkgs, *pkg)
-	}
-
-	bundles := []model.Bundle{}
-	for _, pkg := range pkgs {
-		for _, ch := range pkg.Channels {
-			for _, b := range ch.Bundles {
-				bundles = append(bundles, *b)
+	for _, ch := range updates.Channels {
+		for _, b := range ch.Bundles {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ch.Package, ch.Channel, b.Name, b.Replaces); err != nil {
+				return err
 			}
 		}
 	}
-
-	for _, b := range bundles {
-		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.Package.Name, b.Channel.Name, b.Name, b.Replaces); err != nil {
-			return err
-		}
-	}
-
 	return tw.Flush()
 }
@@ -0,0 +1,96 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogUpdates(t *testing.T) {
+	type spec struct {
+		name     string
+		dc       declcfg.DeclarativeConfig
+		exp      CatalogUpdates
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/NoUpdates",
+			exp:  CatalogUpdates{Catalog: "reg/catalog:latest", Channels: []ChannelUpdates{}},
+		},
+		{
+			name: "Valid/Sorted",
+			dc: declcfg.DeclarativeConfig{
+				Packages: []declcfg.Package{
+					{Schema: "olm.package", Name: "foo", DefaultChannel: "stable"},
+					{Schema: "olm.package", Name: "bar", DefaultChannel: "stable"},
+				},
+				Channels: []declcfg.Channel{
+					{Schema: "olm.channel", Name: "stable", Package: "foo", Entries: []declcfg.ChannelEntry{
+						{Name: "foo.v0.2.0", Replaces: "foo.v0.1.0"},
+						{Name: "foo.v0.1.0"},
+					}},
+					{Schema: "olm.channel", Name: "alpha", Package: "foo", Entries: []declcfg.ChannelEntry{
+						{Name: "foo.v0.2.0"},
+					}},
+					{Schema: "olm.channel", Name: "stable", Package: "bar", Entries: []declcfg.ChannelEntry{
+						{Name: "bar.v0.1.0"},
+					}},
+				},
+				Bundles: []declcfg.Bundle{
+					{
+						Schema:     "olm.bundle",
+						Name:       "foo.v0.1.0",
+						Package:    "foo",
+						Image:      "reg/foo:v0.1.0",
+						Properties: []property.Property{property.MustBuildPackage("foo", "0.1.0")},
+					},
+					{
+						Schema:     "olm.bundle",
+						Name:       "foo.v0.2.0",
+						Package:    "foo",
+						Image:      "reg/foo:v0.2.0",
+						Properties: []property.Property{property.MustBuildPackage("foo", "0.2.0")},
+					},
+					{
+						Schema:     "olm.bundle",
+						Name:       "bar.v0.1.0",
+						Package:    "bar",
+						Image:      "reg/bar:v0.1.0",
+						Properties: []property.Property{property.MustBuildPackage("bar", "0.1.0")},
+					},
+				},
+			},
+			exp: CatalogUpdates{
+				Catalog: "reg/catalog:latest",
+				Channels: []ChannelUpdates{
+					{Package: "bar", Channel: "stable", Bundles: []Bundle{
+						{Name: "bar.v0.1.0", Version: "0.1.0"},
+					}},
+					{Package: "foo", Channel: "alpha", Bundles: []Bundle{
+						{Name: "foo.v0.2.0", Version: "0.2.0"},
+					}},
+					{Package: "foo", Channel: "stable", Bundles: []Bundle{
+						{Name: "foo.v0.1.0", Version: "0.1.0"},
+						{Name: "foo.v0.2.0", Version: "0.2.0", Replaces: "foo.v0.1.0"},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cu, err := catalogUpdates(c.dc, "reg/catalog:latest")
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, cu)
+			}
+		})
+	}
+}
@@ -112,13 +112,26 @@ func GenerateICSP(icspName, icspScope string, byteLimit int, mapping image.Typed
 	if err != nil {
 		return nil, err
 	}
+	return generateICSPs(icspName, byteLimit, registryMapping, builder)
+}
+
+// generateICSPs splits registryMapping into ImageContentSourcePolicy objects
+// created by builder that are no larger than byteLimit when marshaled.
+func generateICSPs(icspName string, byteLimit int, registryMapping map[string]string, builder ICSPBuilder) (icsps []operatorv1alpha1.ImageContentSourcePolicy, err error) {
+	// Add sources in a stable order so generated policies do not change between runs.
+	sources := make([]string, 0, len(registryMapping))
+	for source := range registryMapping {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
 
-	for len(registryMapping) != 0 {
+	var icspCount int
+	for len(sources) != 0 {
 
-		var icspCount int
 		icsp := builder.New(icspName, icspCount)
 
-		for key := range registryMapping {
+		for len(sources) != 0 {
+			key := sources[0]
 			icsp.Spec.RepositoryDigestMirrors = append(icsp.Spec.RepositoryDigestMirrors, operatorv1alpha1.RepositoryDigestMirrors{
 				Source:  key,
 				Mirrors: []string{registryMapping[key]},
@@ -138,7 +151,7 @@ func GenerateICSP(icspName, icspScope string, byteLimit int, mapping image.Typed
 				icspCount++
 				break
 			}
-			delete(registryMapping, key)
+			sources = sources[1:]
 		}
 
 		if len(icsp.Spec.RepositoryDigestMirrors) != 0 {
@@ -165,25 +178,33 @@ func getRegistryMapping(icspScope string, mapping image.TypedImageMapping) (map[
 			logrus.Warnf("no digest mapping available for %s, skip writing to ImageContentSourcePolicy", k)
 			continue
 		}
-		switch {
-		case icspScope == registryICSPScope:
-			registryMapping[k.Ref.Registry] = v.Ref.Registry
-		case icspScope == namespaceICSPScope && k.Ref.Namespace == "":
-			fallthrough
-		case icspScope == repositoryICSPScope:
-			registryMapping[k.Ref.AsRepository().String()] = v.Ref.AsRepository().String()
-		case icspScope == namespaceICSPScope:
-			source := path.Join(k.Ref.Registry, k.Ref.Namespace)
-			dest := path.Join(v.Ref.Registry, v.Ref.Namespace)
-			registryMapping[source] = dest
-		default:
-			return registryMapping, fmt.Errorf("invalid ICSP scope %s", icspScope)
+		source, dest, err := scopeMapping(icspScope, k.Ref, v.Ref)
+		if err != nil {
+			return registryMapping, err
 		}
+		registryMapping[source] = dest
 	}
 
 	return registryMapping, nil
 }
 
+// scopeMapping returns the source and mirror locations
+// of src and dst truncated to the given scope.
+func scopeMapping(scope string, src, dst reference.DockerImageReference) (string, string, error) {
+	switch {
+	case scope == registryICSPScope:
+		return src.Registry, dst.Registry, nil
+	case scope == namespaceICSPScope && src.Namespace == "":
+		fallthrough
+	case scope == repositoryICSPScope:
+		return src.AsRepository().String(), dst.AsRepository().String(), nil
+	case scope == namespaceICSPScope:
+		return path.Join(src.Registry, src.Namespace), path.Join(dst.Registry, dst.Namespace), nil
+	default:
+		return "", "", fmt.Errorf("invalid ICSP scope %s", scope)
+	}
+}
+
 func generateCatalogSource(name string, dest reference.DockerImageReference) ([]byte, error) {
 	// Prefer tag over digest for automatic updates.
 	if dest.Tag != "" {
@@ -218,31 +239,50 @@ func WriteICSPs(dir string, icsps []operatorv1alpha1.ImageContentSourcePolicy) e
 		return nil
 	}
 
-	// Stable ICSP generation.
-	sort.Slice(icsps, func(i, j int) bool {
-		return string(icsps[i].Name) < string(icsps[j].Name)
+	objs := make([]manifestObject, len(icsps))
+	for i := range icsps {
+		objs[i] = manifestObject{name: icsps[i].Name, obj: &icsps[i]}
+	}
+	if err := writeManifests(filepath.Join(dir, "imageContentSourcePolicy.yaml"), icspKind, objs); err != nil {
+		return err
+	}
+
+	logrus.Infof("Wrote ICSP manifests to %s", dir)
+
+	return nil
+}
+
+// manifestObject is a named API object to be written to a manifest file.
+type manifestObject struct {
+	name string
+	obj  interface{}
+}
+
+// writeManifests writes objs of the given kind to path as a
+// multi-document YAML file sorted by name.
+func writeManifests(path, kind string, objs []manifestObject) error {
+	// Stable manifest generation.
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].name < objs[j].name
 	})
 
-	icspBytes := make([][]byte, len(icsps))
-	for i := range icsps {
+	objBytes := make([][]byte, len(objs))
+	for i := range objs {
 		// Create an unstructured object for removing creationTimestamp
-		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&icsps[i])
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(objs[i].obj)
 		if err != nil {
 			return fmt.Errorf("error converting to unstructured: %v", err)
 		}
 		delete(obj["metadata"].(map[string]interface{}), "creationTimestamp")
 
-		if icspBytes[i], err = yaml.Marshal(obj); err != nil {
-			return fmt.Errorf("unable to marshal ImageContentSourcePolicy yaml: %v", err)
+		if objBytes[i], err = yaml.Marshal(obj); err != nil {
+			return fmt.Errorf("unable to marshal %s yaml: %v", kind, err)
 		}
 	}
 
-	if err := ioutil.WriteFile(filepath.Join(dir, "imageContentSourcePolicy.yaml"), aggregateICSPs(icspBytes), os.ModePerm); err != nil {
-		return fmt.Errorf("error writing ImageContentSourcePolicy: %v", err)
+	if err := ioutil.WriteFile(path, aggregateICSPs(objBytes), os.ModePerm); err != nil {
+		return fmt.Errorf("error writing %s: %v", kind, err)
 	}
-
-	logrus.Infof("Wrote ICSP manifests to %s", dir)
-
 	return nil
 }
 
@@ -375,3 +375,25 @@ func TestICSPGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestICSPGenerationSplit(t *testing.T) {
+	registryMapping := map[string]string{
+		"some-registry.io/a": "disconn-registry.io/a",
+		"some-registry.io/b": "disconn-registry.io/b",
+		"some-registry.io/c": "disconn-registry.io/c",
+	}
+	// Fit two mirrors into each ICSP.
+	icsps, err := generateICSPs("test", 650, registryMapping, &GenericBuilder{})
+	require.NoError(t, err)
+	require.Len(t, icsps, 2)
+	require.Equal(t, "test-0", icsps[0].Name)
+	require.Equal(t, []operatorv1alpha1.RepositoryDigestMirrors{
+		{Source: "some-registry.io/a", Mirrors: []string{"disconn-registry.io/a"}},
+		{Source: "some-registry.io/b", Mirrors: []string{"disconn-registry.io/b"}},
+	}, icsps[0].Spec.RepositoryDigestMirrors)
+	require.Equal(t, "test-1", icsps[1].Name)
+	require.Equal(t, []operatorv1alpha1.RepositoryDigestMirrors{
+		{Source: "some-registry.io/c", Mirrors: []string{"disconn-registry.io/c"}},
+	}, icsps[1].Spec.RepositoryDigestMirrors)
+	require.Len(t, registryMapping, 3)
+}
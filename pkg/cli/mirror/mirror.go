@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
@@ -16,7 +17,6 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
 	"github.com/openshift/oc/pkg/cli/image/imagesource"
 	imagemanifest "github.com/openshift/oc/pkg/cli/image/manifest"
 	"github.com/openshift/oc/pkg/cli/image/mirror"
@@ -73,7 +73,10 @@ func NewMirrorCmd() *cobra.Command {
 			# Publish to a registry and add a top-level namespace
 			oc-mirror --from mirror_seq1_000000.tar docker://localhost:5000/namespace
 		`),
-		PersistentPreRun:  o.LogfilePreRun,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			o.LogfilePreRun(cmd, args)
+			o.GraphCachePreRun(cmd, args)
+		},
 		PersistentPostRun: o.LogfilePostRun,
 		Args:              cobra.MinimumNArgs(1),
 		SilenceErrors:     false,
@@ -87,6 +90,9 @@ func NewMirrorCmd() *cobra.Command {
 
 	o.BindFlags(cmd.Flags())
 	o.RootOptions.BindFlags(cmd.PersistentFlags())
+	// Only the kubeconfig location and context are needed to apply results.
+	cmd.Flags().StringVar(kubeConfigFlags.KubeConfig, "kubeconfig", *kubeConfigFlags.KubeConfig, "Path to the kubeconfig file used with --apply")
+	cmd.Flags().StringVar(kubeConfigFlags.Context, "context", *kubeConfigFlags.Context, "The kubeconfig context used with --apply")
 
 	cmd.AddCommand(version.NewVersionCommand(f, o.RootOptions))
 	cmd.AddCommand(list.NewListCommand(f, o.RootOptions))
@@ -151,7 +157,10 @@ func (o *MirrorOptions) Validate() error {
 	// Attempt to login to registry
 	// FIXME(jpower432): CheckPushPermissions is slated for deprecation
 	// must replace with its replacement
-	if len(o.ToMirror) > 0 {
+	switch {
+	case len(o.ToMirror) > 0 && o.DryRun:
+		logrus.Infof("Dry run: skipping push permission check for %s", o.ToMirror)
+	case len(o.ToMirror) > 0:
 		logrus.Infof("Checking push permissions for %s", o.ToMirror)
 		ref := path.Join(o.ToMirror, o.UserNamespace, "oc-mirror")
 		logrus.Debugf("Using image %s to check permissions", ref)
@@ -170,6 +179,38 @@ func (o *MirrorOptions) Validate() error {
 		}
 	}
 
+	if o.Apply {
+		switch {
+		case len(o.ToMirror) == 0:
+			return fmt.Errorf("--apply requires a registry destination")
+		case o.DryRun:
+			return fmt.Errorf("--apply cannot be used with --dry-run")
+		}
+	}
+
+	switch {
+	case o.SignAll && len(o.SignKey) == 0:
+		return fmt.Errorf("--sign-all requires --sign-key")
+	case len(o.SignKey) > 0 && len(o.ToMirror) == 0:
+		return fmt.Errorf("--sign-key requires a registry destination")
+	case len(o.SignKey) > 0:
+		// Load the key up front so a bad key or
+		// password is reported before publishing.
+		key, err := loadSigningKey(o.SignKey)
+		if err != nil {
+			return err
+		}
+		o.signingKey = key
+	}
+
+	if err := o.validatePolicyOptions(); err != nil {
+		return err
+	}
+
+	if _, ok := supportedToolsOS[o.ToolsOS]; !ok {
+		return fmt.Errorf("--tools-os must be one of linux, mac, windows, or *, got %q", o.ToolsOS)
+	}
+
 	var supportedArchs = map[string]struct{}{"amd64": {}, "ppc64le": {}, "s390x": {}}
 	for _, arch := range o.FilterOptions {
 		if _, ok := supportedArchs[arch]; !ok {
@@ -198,6 +239,8 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 
 	var mapping image.TypedImageMapping
 	var meta v1alpha2.Metadata
+	// resultsDir contains the manifests generated for a registry destination
+	var resultsDir string
 	switch {
 	case o.ManifestsOnly:
 		logrus.Info("Not implemented yet")
@@ -222,7 +265,13 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 			if err := image.WriteImageMapping(mapping, mappingPath); err != nil {
 				return err
 			}
-			return nil
+			return o.writeDryRunEstimate(cmd.Context(), cfg, meta, mapping)
+		}
+
+		if !o.SkipCapacityCheck {
+			if err := o.checkCreateCapacity(cmd.Context(), cfg, meta, mapping); err != nil {
+				return err
+			}
 		}
 
 		// Mirror planned images
@@ -260,7 +309,7 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 		// Publish from disk to registry
 		// this takes care of syncing the metadata to the
 		// registry backends and generating the CatalogSource
-		mapping, err = o.Publish(cmd.Context())
+		meta, mapping, err = o.Publish(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -268,9 +317,22 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 		if err != nil {
 			return err
 		}
+		resultsDir = dir
 		if err := o.generateAllICSPs(mapping, dir); err != nil {
 			return err
 		}
+		if err := o.writeCompleteResults(meta, dir); err != nil {
+			return err
+		}
+		if err := o.writeSignatureConfigMaps(dir, filepath.Join(dir, completeResultsDir)); err != nil {
+			return err
+		}
+		if err := o.signResults(cmd.Context(), mapping, dir); err != nil {
+			return err
+		}
+		if o.DryRun {
+			return logDryRunResults(o.OutputDir, dir)
+		}
 	case len(o.ToMirror) > 0 && len(o.ConfigPath) > 0:
 		cfg, err := config.LoadConfig(o.ConfigPath)
 		if err != nil {
@@ -294,20 +356,20 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 			if err := image.WriteImageMapping(mapping, mappingPath); err != nil {
 				return err
 			}
-			return nil
-		}
-
-		// Mirror planned images
-		// TODO(jpower432): Investigate how to mirror to mirror and
-		// specific source and dest TLS configuration
-		if err := o.mirrorMappings(cfg, mapping, destInsecure); err != nil {
-			return err
+		} else {
+			// Mirror planned images
+			// TODO(jpower432): Investigate how to mirror to mirror and
+			// specific source and dest TLS configuration
+			if err := o.mirrorMappings(cfg, mapping, destInsecure); err != nil {
+				return err
+			}
 		}
 		// Process any catalog images
 		dir, err := o.createResultsDir()
 		if err != nil {
 			return err
 		}
+		resultsDir = dir
 		if len(cfg.Mirror.Operators) > 0 {
 			ctlgRefs, err := o.rebuildCatalogs(cmd.Context(), filepath.Join(o.Dir, config.SourceDir))
 			if err != nil {
@@ -321,6 +383,21 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 		if err := o.generateAllICSPs(mapping, dir); err != nil {
 			return err
 		}
+		meta.MirroredImages = mergeMirroredImages(meta.MirroredImages, mapping)
+		if err := o.writeCompleteResults(meta, dir); err != nil {
+			return err
+		}
+		if err := o.writeSignatureConfigMaps(dir, filepath.Join(dir, completeResultsDir)); err != nil {
+			return err
+		}
+		if err := o.signResults(cmd.Context(), mapping, dir); err != nil {
+			return err
+		}
+		if o.DryRun {
+			logrus.Infof("Dry run: would update metadata sequence from %d to %d",
+				meta.PastMirror.Sequence-1, meta.PastMirror.Sequence)
+			return logDryRunResults(dir)
+		}
 		// Move charts into results dir
 		srcHelmPath := filepath.Join(o.Dir, config.SourceDir, config.HelmDir)
 		dstHelmPath := filepath.Join(dir, config.HelmDir)
@@ -328,6 +405,10 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 			return err
 		}
 		logrus.Debugf("Moved any downloaded Helm chart to %s", dir)
+		srcToolsPath := filepath.Join(o.Dir, config.SourceDir, config.ToolsDir)
+		if err := moveReleaseTools(srcToolsPath, filepath.Join(dir, config.ToolsDir)); err != nil {
+			return err
+		}
 		// Sync metadata from disk to source and target backends
 		if cfg.StorageConfig.IsSet() {
 			sourceBackend, err := storage.ByConfig(o.Dir, cfg.StorageConfig)
@@ -359,6 +440,12 @@ func (o *MirrorOptions) Run(cmd *cobra.Command, f kcmdutil.Factory) (err error)
 		}
 	}
 
+	if o.Apply && len(resultsDir) > 0 {
+		if err := o.applyResults(cmd.Context(), f, resultsDir); err != nil {
+			return err
+		}
+	}
+
 	if !o.SkipCleanup {
 		if err := os.RemoveAll(filepath.Join(o.Dir, config.SourceDir)); err != nil {
 			return err
@@ -382,11 +469,28 @@ func (o *MirrorOptions) getNameOpts() (options []name.Option) {
 	return options
 }
 
-func (o *MirrorOptions) createRT() http.RoundTripper {
-	var insecure bool
-	if o.DestPlainHTTP || o.DestSkipTLS {
-		insecure = true
+// getSourceRemoteOpts returns remote options for
+// reading from source registries.
+func (o *MirrorOptions) getSourceRemoteOpts(ctx context.Context) []remote.Option {
+	return []remote.Option{
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithTransport(newTransport(o.SourcePlainHTTP || o.SourceSkipTLS)),
+		remote.WithContext(ctx),
 	}
+}
+
+func (o *MirrorOptions) getSourceNameOpts() (options []name.Option) {
+	if o.SourceSkipTLS || o.SourcePlainHTTP {
+		options = append(options, name.Insecure)
+	}
+	return options
+}
+
+func (o *MirrorOptions) createRT() http.RoundTripper {
+	return newTransport(o.DestPlainHTTP || o.DestSkipTLS)
+}
+
+func newTransport(insecure bool) http.RoundTripper {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -461,6 +565,11 @@ func (o *MirrorOptions) newMirrorImageOptions(insecure bool) (*mirror.MirrorImag
 }
 
 func (o *MirrorOptions) createResultsDir() (resultsDir string, err error) {
+	// Dry runs generate results in a temporary directory
+	// so the workspace is left untouched.
+	if o.DryRun {
+		return ioutil.TempDir("", "results-")
+	}
 	resultsDir = filepath.Join(
 		o.Dir,
 		fmt.Sprintf("results-%v", time.Now().Unix()),
@@ -471,36 +580,39 @@ func (o *MirrorOptions) createResultsDir() (resultsDir string, err error) {
 	return resultsDir, nil
 }
 
-func (o *MirrorOptions) newMetadataImage(uid string) string {
-	repo := path.Join(o.ToMirror, o.UserNamespace, "oc-mirror")
-	return fmt.Sprintf("%s:%s", repo, uid)
-}
-
-func (o *MirrorOptions) generateAllICSPs(mapping image.TypedImageMapping, dir string) error {
-
-	allICSPs := []operatorv1alpha1.ImageContentSourcePolicy{}
-	releases := image.ByCategory(mapping, image.TypeOCPRelease)
-	generic := image.ByCategory(mapping, image.TypeGeneric)
-	operator := image.ByCategory(mapping, image.TypeOperatorBundle, image.TypeOperatorCatalog)
-
-	getICSP := func(mapping image.TypedImageMapping, name string, builder ICSPBuilder) error {
-		icsps, err := GenerateICSP(name, namespaceICSPScope, icspSizeLimit, mapping, builder)
+// logDryRunResults logs the manifests generated in the temporary
+// results dirs during a dry run and removes the dirs.
+func logDryRunResults(dirs ...string) error {
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(fpath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, fpath)
+			if err != nil {
+				return err
+			}
+			data, err := ioutil.ReadFile(filepath.Clean(fpath))
+			if err != nil {
+				return err
+			}
+			logrus.Infof("Dry run: would generate %s:\n%s", rel, data)
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("error generating ICSP manifests")
+			return err
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
 		}
-		allICSPs = append(allICSPs, icsps...)
-		return nil
-	}
-
-	if err := getICSP(releases, "release", &ReleaseBuilder{}); err != nil {
-		return err
-	}
-	if err := getICSP(generic, "generic", &GenericBuilder{}); err != nil {
-		return err
-	}
-	if err := getICSP(operator, "operator", &OperatorBuilder{}); err != nil {
-		return err
 	}
+	return nil
+}
 
-	return WriteICSPs(dir, allICSPs)
+func (o *MirrorOptions) newMetadataImage(uid string) string {
+	repo := path.Join(o.ToMirror, o.UserNamespace, "oc-mirror")
+	return fmt.Sprintf("%s:%s", repo, uid)
 }
@@ -191,6 +191,15 @@ func TestMirrorValidate(t *testing.T) {
 			},
 			expError: "architecture \"arm64\" is not a supported release architecture",
 		},
+		{
+			name: "Invalid/ToolsOS",
+			opts: &MirrorOptions{
+				ConfigPath: "foo",
+				OutputDir:  "dir",
+				ToolsOS:    "plan9",
+			},
+			expError: "--tools-os must be one of linux, mac, windows, or *, got \"plan9\"",
+		},
 		{
 			name: "Valid/MirrortoDisk",
 			opts: &MirrorOptions{
@@ -0,0 +1,184 @@
+package mirror
+
+import (
+	"path/filepath"
+
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// The config.openshift.io/v1 mirror set types are not available in the
+// vendored openshift/api version, so the subset of the API needed to
+// generate manifests is defined here.
+
+const (
+	configV1APIVersion = "config.openshift.io/v1"
+	idmsKind           = "ImageDigestMirrorSet"
+	itmsKind           = "ImageTagMirrorSet"
+)
+
+// Supported values for --policy-format.
+const (
+	policyFormatICSP      = "icsp"
+	policyFormatMirrorSet = "mirrorset"
+	policyFormatAll       = "all"
+)
+
+// MirrorSourcePolicy defines the fallback policy when pulls from mirrors fail.
+type MirrorSourcePolicy string
+
+const (
+	// NeverContactSource prevents falling back to the source when all mirrors fail.
+	NeverContactSource MirrorSourcePolicy = "NeverContactSource"
+	// AllowContactingSource allows falling back to the source when all mirrors fail.
+	AllowContactingSource MirrorSourcePolicy = "AllowContactingSource"
+)
+
+// ImageMirror is a registry, namespace, or repository mirroring a source.
+type ImageMirror string
+
+// ImageDigestMirrorSet holds cluster-wide information about
+// how to handle registry mirror rules for pulls by digest.
+type ImageDigestMirrorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ImageDigestMirrorSetSpec `json:"spec"`
+}
+
+// ImageDigestMirrorSetSpec is the specification of an ImageDigestMirrorSet.
+type ImageDigestMirrorSetSpec struct {
+	ImageDigestMirrors []ImageDigestMirrors `json:"imageDigestMirrors"`
+}
+
+// ImageDigestMirrors holds the mirrors for a digest-referenced source.
+type ImageDigestMirrors struct {
+	Source             string             `json:"source"`
+	Mirrors            []ImageMirror      `json:"mirrors,omitempty"`
+	MirrorSourcePolicy MirrorSourcePolicy `json:"mirrorSourcePolicy,omitempty"`
+}
+
+// ImageTagMirrorSet holds cluster-wide information about
+// how to handle registry mirror rules for pulls by tag.
+type ImageTagMirrorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ImageTagMirrorSetSpec `json:"spec"`
+}
+
+// ImageTagMirrorSetSpec is the specification of an ImageTagMirrorSet.
+type ImageTagMirrorSetSpec struct {
+	ImageTagMirrors []ImageTagMirrors `json:"imageTagMirrors"`
+}
+
+// ImageTagMirrors holds the mirrors for a tag-referenced source.
+type ImageTagMirrors struct {
+	Source             string             `json:"source"`
+	Mirrors            []ImageMirror      `json:"mirrors,omitempty"`
+	MirrorSourcePolicy MirrorSourcePolicy `json:"mirrorSourcePolicy,omitempty"`
+}
+
+// convertToIDMS returns the ImageDigestMirrorSet equivalent of each ImageContentSourcePolicy.
+func convertToIDMS(icsps []operatorv1alpha1.ImageContentSourcePolicy) []ImageDigestMirrorSet {
+	var idmss []ImageDigestMirrorSet
+	for _, icsp := range icsps {
+		idms := ImageDigestMirrorSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: configV1APIVersion, Kind: idmsKind},
+			ObjectMeta: icsp.ObjectMeta,
+		}
+		for _, rdm := range icsp.Spec.RepositoryDigestMirrors {
+			idms.Spec.ImageDigestMirrors = append(idms.Spec.ImageDigestMirrors, ImageDigestMirrors{
+				Source:  rdm.Source,
+				Mirrors: toImageMirrors(rdm.Mirrors),
+			})
+		}
+		idmss = append(idmss, idms)
+	}
+	return idmss
+}
+
+// convertToITMS returns the ImageTagMirrorSet equivalent of each ImageContentSourcePolicy.
+// ICSPs and ImageDigestMirrorSets only apply to pulls by digest, so images referenced
+// by tag cannot be redirected to the mirror without an ImageTagMirrorSet.
+func convertToITMS(icsps []operatorv1alpha1.ImageContentSourcePolicy) []ImageTagMirrorSet {
+	var itmss []ImageTagMirrorSet
+	for _, icsp := range icsps {
+		itms := ImageTagMirrorSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: configV1APIVersion, Kind: itmsKind},
+			ObjectMeta: icsp.ObjectMeta,
+		}
+		for _, rdm := range icsp.Spec.RepositoryDigestMirrors {
+			itms.Spec.ImageTagMirrors = append(itms.Spec.ImageTagMirrors, ImageTagMirrors{
+				Source:  rdm.Source,
+				Mirrors: toImageMirrors(rdm.Mirrors),
+			})
+		}
+		itmss = append(itmss, itms)
+	}
+	return itmss
+}
+
+// getTagRegistryMapping returns the scoped source to mirror mapping
+// for images referenced by tag.
+func getTagRegistryMapping(scope string, mapping image.TypedImageMapping) (map[string]string, error) {
+	registryMapping := map[string]string{}
+	for k, v := range mapping {
+		if len(k.Ref.Tag) == 0 {
+			continue
+		}
+		if len(v.Ref.Tag) == 0 {
+			logrus.Warnf("no tag mapping available for %s, skip writing to ImageTagMirrorSet", k)
+			continue
+		}
+		source, dest, err := scopeMapping(scope, k.Ref, v.Ref)
+		if err != nil {
+			return registryMapping, err
+		}
+		registryMapping[source] = dest
+	}
+	return registryMapping, nil
+}
+
+func toImageMirrors(mirrors []string) []ImageMirror {
+	imageMirrors := make([]ImageMirror, len(mirrors))
+	for i, m := range mirrors {
+		imageMirrors[i] = ImageMirror(m)
+	}
+	return imageMirrors
+}
+
+// WriteIDMS will write provided ImageDigestMirrorSet objects to disk
+func WriteIDMS(dir string, idmss []ImageDigestMirrorSet) error {
+	if len(idmss) == 0 {
+		logrus.Debug("No ImageDigestMirrorSets generated to write")
+		return nil
+	}
+	objs := make([]manifestObject, len(idmss))
+	for i := range idmss {
+		objs[i] = manifestObject{name: idmss[i].Name, obj: &idmss[i]}
+	}
+	if err := writeManifests(filepath.Join(dir, "imageDigestMirrorSet.yaml"), idmsKind, objs); err != nil {
+		return err
+	}
+	logrus.Infof("Wrote ImageDigestMirrorSet manifests to %s", dir)
+	return nil
+}
+
+// WriteITMS will write provided ImageTagMirrorSet objects to disk
+func WriteITMS(dir string, itmss []ImageTagMirrorSet) error {
+	if len(itmss) == 0 {
+		logrus.Debug("No ImageTagMirrorSets generated to write")
+		return nil
+	}
+	objs := make([]manifestObject, len(itmss))
+	for i := range itmss {
+		objs[i] = manifestObject{name: itmss[i].Name, obj: &itmss[i]}
+	}
+	if err := writeManifests(filepath.Join(dir, "imageTagMirrorSet.yaml"), itmsKind, objs); err != nil {
+		return err
+	}
+	logrus.Infof("Wrote ImageTagMirrorSet manifests to %s", dir)
+	return nil
+}
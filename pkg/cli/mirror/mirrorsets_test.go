@@ -0,0 +1,128 @@
+package mirror
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestIDMSGeneration(t *testing.T) {
+	src, err := image.ParseTypedImage("some-registry.io/namespace/image@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001", image.TypeOperatorBundle)
+	require.NoError(t, err)
+	dst, err := image.ParseTypedImage("disconn-registry.io/namespace/image@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001", image.TypeOperatorBundle)
+	require.NoError(t, err)
+
+	icsps, err := GenerateICSP("test", repositoryICSPScope, icspSizeLimit, image.TypedImageMapping{src: dst}, &OperatorBuilder{})
+	require.NoError(t, err)
+	idmss := convertToIDMS(icsps)
+	exp := []ImageDigestMirrorSet{{
+		TypeMeta: metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "ImageDigestMirrorSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-0",
+			Labels: map[string]string{"operators.openshift.org/catalog": "true"},
+		},
+		Spec: ImageDigestMirrorSetSpec{
+			ImageDigestMirrors: []ImageDigestMirrors{{
+				Source:  "some-registry.io/namespace/image",
+				Mirrors: []ImageMirror{"disconn-registry.io/namespace/image"},
+			}},
+		},
+	}}
+	require.Equal(t, exp, idmss)
+}
+
+func TestITMSGeneration(t *testing.T) {
+	type spec struct {
+		name     string
+		src      string
+		dst      string
+		scope    string
+		expected []ImageTagMirrorSet
+		err      string
+	}
+
+	cases := []spec{
+		{
+			name:  "Valid/TagReference",
+			src:   "some-registry.io/namespace/image:v1@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			dst:   "disconn-registry.io/namespace/image:v1",
+			scope: namespaceICSPScope,
+			expected: []ImageTagMirrorSet{{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "ImageTagMirrorSet"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-0"},
+				Spec: ImageTagMirrorSetSpec{
+					ImageTagMirrors: []ImageTagMirrors{{
+						Source:  "some-registry.io/namespace",
+						Mirrors: []ImageMirror{"disconn-registry.io/namespace"},
+					}},
+				},
+			}},
+		},
+		{
+			name:  "Valid/DigestReference",
+			src:   "some-registry.io/namespace/image@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			dst:   "disconn-registry.io/namespace/image:oc-mirror",
+			scope: namespaceICSPScope,
+		},
+		{
+			name:  "Invalid/Scope",
+			src:   "some-registry.io/namespace/image:v1",
+			dst:   "disconn-registry.io/namespace/image:v1",
+			scope: "invalid",
+			err:   "invalid ICSP scope invalid",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			src, err := image.ParseTypedImage(c.src, image.TypeGeneric)
+			require.NoError(t, err)
+			dst, err := image.ParseTypedImage(c.dst, image.TypeGeneric)
+			require.NoError(t, err)
+
+			tagMapping, err := getTagRegistryMapping(c.scope, image.TypedImageMapping{src: dst})
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			icsps, err := generateICSPs("test", icspSizeLimit, tagMapping, &GenericBuilder{})
+			require.NoError(t, err)
+			require.Equal(t, c.expected, convertToITMS(icsps))
+		})
+	}
+}
+
+func TestWriteITMS(t *testing.T) {
+	dir := t.TempDir()
+	itmss := []ImageTagMirrorSet{{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "ImageTagMirrorSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "generic-0"},
+		Spec: ImageTagMirrorSetSpec{
+			ImageTagMirrors: []ImageTagMirrors{{
+				Source:  "some-registry.io/namespace",
+				Mirrors: []ImageMirror{"disconn-registry.io/namespace"},
+			}},
+		},
+	}}
+	require.NoError(t, WriteITMS(dir, itmss))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "imageTagMirrorSet.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, `---
+apiVersion: config.openshift.io/v1
+kind: ImageTagMirrorSet
+metadata:
+  name: generic-0
+spec:
+  imageTagMirrors:
+  - mirrors:
+    - disconn-registry.io/namespace
+    source: some-registry.io/namespace
+`, string(data))
+}
@@ -2,6 +2,7 @@ package mirror
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"os"
 	"os/signal"
 	"sync"
@@ -32,6 +33,52 @@ type MirrorOptions struct {
 	SkipMissing      bool
 	ContinueOnError  bool
 	FilterOptions    []string
+	// SkipCapacityCheck disables checking for
+	// sufficient free disk space before mirroring
+	SkipCapacityCheck bool
+	// PolicyFormat selects the image mirror
+	// policy manifests written to the results directory
+	PolicyFormat string
+	// PolicyScopes maps image categories to the
+	// scope of their image mirror policies
+	PolicyScopes map[string]string
+	// PolicyNamePrefix is prepended to the
+	// names of generated image mirror policies
+	PolicyNamePrefix string
+	// PolicyLabels are added to generated image mirror policies
+	PolicyLabels map[string]string
+	// ConsolidatePolicies generates a single set of image
+	// mirror policies for all image categories
+	ConsolidatePolicies bool
+	// Apply server-side applies the generated
+	// resources to a cluster after publishing
+	Apply bool
+	// ReleaseSignatureStore is the URL of the store
+	// release signatures are collected from
+	ReleaseSignatureStore string
+	// ReleaseSignatureDir is a local directory
+	// release signatures are collected from
+	ReleaseSignatureDir string
+	// ReleaseKeyring is the path to a GPG keyring
+	// release signatures are verified against
+	ReleaseKeyring string
+	// IncludeTools extracts the oc and openshift-install
+	// archives of planned releases into the imageset
+	IncludeTools bool
+	// ToolsOS is the operating system of the extracted
+	// tools, or * for every operating system
+	ToolsOS string
+	// IncludeRelatedArtifacts enables mirroring the signatures,
+	// attestations, SBOMs, and referrers attached to images
+	IncludeRelatedArtifacts bool
+	// SignKey is the path to a cosign-compatible private
+	// key used to sign images pushed to the registry
+	SignKey string
+	// SignAll signs every image pushed to the
+	// registry instead of only rebuilt catalogs
+	SignAll bool
+	// signingKey is the key loaded from SignKey
+	signingKey *ecdsa.PrivateKey
 	// cancelCh is a channel listening for command cancellations
 	cancelCh <-chan struct{}
 	once     sync.Once
@@ -42,13 +89,13 @@ func (o *MirrorOptions) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.SkipImagePin, "skip-image-pin", o.SkipImagePin, "Do not replace image tags with digest pins in operator catalogs")
 	fs.StringVar(&o.From, "from", o.From, "The path to an input file (e.g. archived imageset)")
 	fs.BoolVar(&o.ManifestsOnly, "manifests-only", o.ManifestsOnly, "Generate manifests and do not mirror")
-	fs.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print actions without mirroring images "+
-		"(experimental: only works for mirror to disk)")
+	fs.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print actions without mirroring images, "+
+		"rebuilding catalogs, or updating metadata")
 	fs.BoolVar(&o.SourceSkipTLS, "source-skip-tls", o.SourceSkipTLS, "Disable TLS validation for source registry")
 	fs.BoolVar(&o.DestSkipTLS, "dest-skip-tls", o.DestSkipTLS, "Disable TLS validation for destination registry")
 	fs.BoolVar(&o.SourcePlainHTTP, "source-use-http", o.SourcePlainHTTP, "Use plain HTTP for source registry")
 	fs.BoolVar(&o.DestPlainHTTP, "dest-use-http", o.DestPlainHTTP, "Use plain HTTP for destination registry")
-	fs.BoolVar(&o.SkipVerification, "skip-verification", o.SkipVerification, "Skip digest verification")
+	fs.BoolVar(&o.SkipVerification, "skip-verification", o.SkipVerification, "Skip digest and release signature verification")
 	fs.BoolVar(&o.SkipCleanup, "skip-cleanup", o.SkipCleanup, "Skip removal of artifact directories")
 	fs.StringSliceVar(&o.FilterOptions, "filter-by-os", o.FilterOptions, "A regular expression to control which release image is picked when multiple variants are available")
 	fs.BoolVar(&o.ContinueOnError, "continue-on-error", o.ContinueOnError, "If an error occurs, keep going "+
@@ -56,6 +103,41 @@ func (o *MirrorOptions) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.SkipMissing, "skip-missing", o.SkipMissing, "If an input image is not found, skip them. "+
 		"404/NotFound errors encountered while pulling images explicitly specified in the config "+
 		"will not be skipped")
+	fs.BoolVar(&o.SkipCapacityCheck, "skip-capacity-check", o.SkipCapacityCheck, "Skip checking for sufficient "+
+		"free disk space before mirroring to disk or publishing")
+	fs.StringVar(&o.PolicyFormat, "policy-format", policyFormatICSP, "Format of the generated image mirror policies: "+
+		"icsp (ImageContentSourcePolicy), mirrorset (ImageDigestMirrorSet and ImageTagMirrorSet), or all")
+	fs.StringToStringVar(&o.PolicyScopes, "policy-scope", o.PolicyScopes, "Scope of the generated image mirror "+
+		"policies per image category (e.g. generic=registry,operator=repository). Categories are generic and operator. "+
+		"Scopes are registry, namespace, and repository. Release policies always use repository scope")
+	fs.StringVar(&o.PolicyNamePrefix, "policy-name-prefix", o.PolicyNamePrefix, "Prefix for the names of generated "+
+		"image mirror policies")
+	fs.StringToStringVar(&o.PolicyLabels, "policy-labels", o.PolicyLabels, "Labels to add to generated image "+
+		"mirror policies (e.g. team=infra,env=prod)")
+	fs.BoolVar(&o.ConsolidatePolicies, "consolidate-policies", o.ConsolidatePolicies, "Generate a single image "+
+		"mirror policy for all image categories instead of one per category")
+	fs.BoolVar(&o.Apply, "apply", o.Apply, "Apply the generated image mirror policies, CatalogSources, "+
+		"and other cluster resources to the cluster in the current kubeconfig context after publishing")
+	fs.StringVar(&o.ReleaseSignatureStore, "release-signature-store", o.ReleaseSignatureStore, "URL of the "+
+		"store release signatures are collected from. Signatures are only collected when this, "+
+		"--release-signature-dir, or --release-keyring is set. Defaults to "+defaultReleaseSignatureStore+
+		" when only --release-keyring is set")
+	fs.StringVar(&o.ReleaseSignatureDir, "release-signature-dir", o.ReleaseSignatureDir, "Local directory release "+
+		"signatures are collected from instead of the signature store. Signatures must be stored as "+
+		"<algo>=<hash>/signature-<n>")
+	fs.BoolVar(&o.IncludeRelatedArtifacts, "include-related-artifacts", o.IncludeRelatedArtifacts, "Mirror the Sigstore "+
+		"signatures, attestations, SBOMs, and OCI referrers attached to planned images")
+	fs.StringVar(&o.SignKey, "sign-key", o.SignKey, "Path to a cosign-compatible ECDSA private key used to sign "+
+		"rebuilt catalog images. Encrypted keys are decrypted with the password in $"+cosignPasswordEnv)
+	fs.BoolVar(&o.SignAll, "sign-all", o.SignAll, "Sign every image pushed to the registry, not only rebuilt "+
+		"catalog images. Requires --sign-key")
+	fs.StringVar(&o.ReleaseKeyring, "release-keyring", o.ReleaseKeyring, "Path to an armored or binary GPG keyring. "+
+		"When set, release payloads without a valid signature from the keyring, including releases not "+
+		"referenced by digest, are not mirrored unless --skip-verification is set")
+	fs.BoolVar(&o.IncludeTools, "include-tools", o.IncludeTools, "Extract the oc and openshift-install archives "+
+		"of each planned release into the imageset. The archives are written to the results directory at publish")
+	fs.StringVar(&o.ToolsOS, "tools-os", "*", "Operating system of the tools extracted with --include-tools "+
+		"(linux, mac, windows, or * for all)")
 
 	// TODO(jpower432): Make this flag visible again once release architecture selection
 	// has been more thouroughly vetted
@@ -92,12 +92,7 @@ func (o *MirrorOptions) Pack(ctx context.Context, assocs image.AssociationSet, m
 
 func (o *MirrorOptions) prepareArchive(ctx context.Context, backend storage.Backend, archiveSize int64, seq int, manifests []v1alpha2.Manifest, blobs []v1alpha2.Blob) error {
 
-	segSize := defaultSegSize
-	if archiveSize != 0 {
-		segSize = archiveSize
-		logrus.Debugf("Using user provided archive size %d GiB", segSize)
-	}
-	segSize *= segMultiplier
+	segSize := segmentSize(archiveSize)
 
 	// Set get absolute path to output dir
 	// to avoid issue with directory change
@@ -125,6 +120,17 @@ func (o *MirrorOptions) prepareArchive(ctx context.Context, backend storage.Back
 	return nil
 }
 
+// segmentSize returns the maximum archive size in bytes
+// for a user provided archiveSize in GiB.
+func segmentSize(archiveSize int64) int64 {
+	segSize := defaultSegSize
+	if archiveSize != 0 {
+		segSize = archiveSize
+		logrus.Debugf("Using user provided archive size %d GiB", segSize)
+	}
+	return segSize * segMultiplier
+}
+
 func (o *MirrorOptions) getFiles(meta v1alpha2.Metadata) ([]v1alpha2.Manifest, []v1alpha2.Blob, error) {
 	diskPath := filepath.Join(o.Dir, config.SourceDir, config.V2Dir)
 	// Define a map that associates locations
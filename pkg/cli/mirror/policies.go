@@ -0,0 +1,222 @@
+package mirror
+
+import (
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// Image categories used to group image mirror policies.
+const (
+	releasePolicyCategory  = "release"
+	genericPolicyCategory  = "generic"
+	operatorPolicyCategory = "operator"
+	// consolidatedPolicyName is the name of the policies
+	// generated for all image categories together.
+	consolidatedPolicyName = "mirror"
+)
+
+// defaultPolicyScopes are the policy scopes used for image categories
+// that are not set with --policy-scope. Release policies always use
+// repository scope.
+var defaultPolicyScopes = map[string]string{
+	releasePolicyCategory:  repositoryICSPScope,
+	genericPolicyCategory:  namespaceICSPScope,
+	operatorPolicyCategory: namespaceICSPScope,
+}
+
+// policyGroup is a set of source to mirror locations
+// written to image mirror policies with the same name.
+type policyGroup struct {
+	name            string
+	registryMapping map[string]string
+	builder         ICSPBuilder
+}
+
+var _ ICSPBuilder = &labeledBuilder{}
+
+// labeledBuilder adds labels to the policies created by an ICSPBuilder.
+type labeledBuilder struct {
+	ICSPBuilder
+	labels map[string]string
+}
+
+func (b *labeledBuilder) New(icspName string, icspCount int) operatorv1alpha1.ImageContentSourcePolicy {
+	icsp := b.ICSPBuilder.New(icspName, icspCount)
+	if len(b.labels) == 0 {
+		return icsp
+	}
+	if icsp.Labels == nil {
+		icsp.Labels = make(map[string]string, len(b.labels))
+	}
+	for k, v := range b.labels {
+		icsp.Labels[k] = v
+	}
+	return icsp
+}
+
+// validatePolicyOptions checks the options used to generate image mirror policies.
+func (o *MirrorOptions) validatePolicyOptions() error {
+	switch o.PolicyFormat {
+	case "", policyFormatICSP, policyFormatMirrorSet, policyFormatAll:
+	default:
+		return fmt.Errorf("invalid policy format %q: must be one of %s, %s, or %s",
+			o.PolicyFormat, policyFormatICSP, policyFormatMirrorSet, policyFormatAll)
+	}
+
+	// Release images are mirrored to a different repository name, so only
+	// repository scope maps their sources correctly and it cannot be set.
+	for category, scope := range o.PolicyScopes {
+		switch category {
+		case genericPolicyCategory, operatorPolicyCategory:
+			switch scope {
+			case registryICSPScope, namespaceICSPScope, repositoryICSPScope:
+			default:
+				return fmt.Errorf("invalid scope %q for %s policies: must be one of %s, %s, or %s",
+					scope, category, registryICSPScope, namespaceICSPScope, repositoryICSPScope)
+			}
+		default:
+			return fmt.Errorf("invalid policy category %q: must be %s or %s",
+				category, genericPolicyCategory, operatorPolicyCategory)
+		}
+	}
+
+	if len(o.PolicyNamePrefix) > 0 {
+		if errs := validation.IsDNS1123Subdomain(o.policyName(operatorPolicyCategory) + "-0"); len(errs) != 0 {
+			return fmt.Errorf("invalid policy name prefix %q: %s", o.PolicyNamePrefix, strings.Join(errs, ", "))
+		}
+	}
+
+	for key, value := range o.PolicyLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return fmt.Errorf("invalid policy label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			return fmt.Errorf("invalid policy label value %q: %s", value, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// policyScope returns the policy scope for an image category.
+func (o *MirrorOptions) policyScope(category string) string {
+	if scope, ok := o.PolicyScopes[category]; ok {
+		return scope
+	}
+	return defaultPolicyScopes[category]
+}
+
+// policyName returns name with the configured policy name prefix.
+func (o *MirrorOptions) policyName(name string) string {
+	if len(o.PolicyNamePrefix) == 0 {
+		return name
+	}
+	return o.PolicyNamePrefix + "-" + name
+}
+
+// policyGroups returns the policy groups for the release, generic, and operator images
+// in mapping, or a single group for all images if policies are consolidated.
+func (o *MirrorOptions) policyGroups(mapping image.TypedImageMapping) ([]policyGroup, error) {
+	categories := []struct {
+		name    string
+		mapping image.TypedImageMapping
+		builder ICSPBuilder
+	}{
+		{releasePolicyCategory, image.ByCategory(mapping, image.TypeOCPRelease), &ReleaseBuilder{}},
+		{genericPolicyCategory, image.ByCategory(mapping, image.TypeGeneric), &GenericBuilder{}},
+		{operatorPolicyCategory, image.ByCategory(mapping, image.TypeOperatorBundle, image.TypeOperatorCatalog), &OperatorBuilder{}},
+	}
+
+	groups := make([]policyGroup, 0, len(categories))
+	for _, category := range categories {
+		registryMapping, err := category.builder.GetMapping(o.policyScope(category.name), category.mapping)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, policyGroup{
+			name:            o.policyName(category.name),
+			registryMapping: registryMapping,
+			builder:         &labeledBuilder{ICSPBuilder: category.builder, labels: o.PolicyLabels},
+		})
+	}
+
+	if !o.ConsolidatePolicies {
+		return groups, nil
+	}
+
+	consolidated := policyGroup{
+		name:            o.policyName(consolidatedPolicyName),
+		registryMapping: map[string]string{},
+		builder:         &labeledBuilder{ICSPBuilder: &GenericBuilder{}, labels: o.PolicyLabels},
+	}
+	for i, group := range groups {
+		for source, mirror := range group.registryMapping {
+			if existing, found := consolidated.registryMapping[source]; found && existing != mirror {
+				return nil, fmt.Errorf("unable to consolidate policies: %s is mirrored to both %s and %s", source, existing, mirror)
+			}
+			consolidated.registryMapping[source] = mirror
+		}
+		// Keep the catalog label when the policy contains operator images.
+		if categories[i].name == operatorPolicyCategory && len(group.registryMapping) != 0 {
+			consolidated.builder = &labeledBuilder{ICSPBuilder: &OperatorBuilder{}, labels: o.PolicyLabels}
+		}
+	}
+	return []policyGroup{consolidated}, nil
+}
+
+// generateAllICSPs writes the image mirror policies for mapping to dir in the
+// format selected with --policy-format along with the equivalent node and
+// installer configuration.
+func (o *MirrorOptions) generateAllICSPs(mapping image.TypedImageMapping, dir string) error {
+	groups, err := o.policyGroups(mapping)
+	if err != nil {
+		return err
+	}
+	if err := o.writeRegistryConfigs(groups, dir); err != nil {
+		return err
+	}
+
+	allICSPs := []operatorv1alpha1.ImageContentSourcePolicy{}
+	for _, group := range groups {
+		icsps, err := generateICSPs(group.name, icspSizeLimit, group.registryMapping, group.builder)
+		if err != nil {
+			return fmt.Errorf("error generating ICSP manifests: %v", err)
+		}
+		allICSPs = append(allICSPs, icsps...)
+	}
+
+	if o.PolicyFormat != policyFormatMirrorSet {
+		if err := WriteICSPs(dir, allICSPs); err != nil {
+			return err
+		}
+	}
+	if o.PolicyFormat != policyFormatMirrorSet && o.PolicyFormat != policyFormatAll {
+		return nil
+	}
+
+	if err := WriteIDMS(dir, convertToIDMS(allICSPs)); err != nil {
+		return err
+	}
+
+	// Additional and Helm images may be pulled by tag, which
+	// digest mirror policies do not redirect.
+	itmsName := o.policyName(genericPolicyCategory)
+	if o.ConsolidatePolicies {
+		itmsName = o.policyName(consolidatedPolicyName)
+	}
+	tagMapping, err := getTagRegistryMapping(o.policyScope(genericPolicyCategory), image.ByCategory(mapping, image.TypeGeneric))
+	if err != nil {
+		return err
+	}
+	itmsBuilder := &labeledBuilder{ICSPBuilder: &GenericBuilder{}, labels: o.PolicyLabels}
+	tagPolicies, err := generateICSPs(itmsName, icspSizeLimit, tagMapping, itmsBuilder)
+	if err != nil {
+		return fmt.Errorf("error generating ImageTagMirrorSet manifests: %v", err)
+	}
+	return WriteITMS(dir, convertToITMS(tagPolicies))
+}
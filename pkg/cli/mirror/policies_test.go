@@ -0,0 +1,321 @@
+package mirror
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+func TestValidatePolicyOptions(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *MirrorOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/Defaults",
+			opts: &MirrorOptions{},
+		},
+		{
+			name: "Valid/AllOptions",
+			opts: &MirrorOptions{
+				PolicyFormat:     policyFormatAll,
+				PolicyScopes:     map[string]string{"generic": "registry", "operator": "repository"},
+				PolicyNamePrefix: "cluster-a",
+				PolicyLabels:     map[string]string{"example.com/team": "infra"},
+			},
+		},
+		{
+			name:     "Invalid/Format",
+			opts:     &MirrorOptions{PolicyFormat: "yaml"},
+			expError: `invalid policy format "yaml": must be one of icsp, mirrorset, or all`,
+		},
+		{
+			name:     "Invalid/ReleaseScope",
+			opts:     &MirrorOptions{PolicyScopes: map[string]string{"release": "repository"}},
+			expError: `invalid policy category "release": must be generic or operator`,
+		},
+		{
+			name:     "Invalid/Scope",
+			opts:     &MirrorOptions{PolicyScopes: map[string]string{"generic": "image"}},
+			expError: `invalid scope "image" for generic policies: must be one of registry, namespace, or repository`,
+		},
+		{
+			name:     "Invalid/Category",
+			opts:     &MirrorOptions{PolicyScopes: map[string]string{"helm": "registry"}},
+			expError: `invalid policy category "helm": must be generic or operator`,
+		},
+		{
+			name:     "Invalid/NamePrefix",
+			opts:     &MirrorOptions{PolicyNamePrefix: "Cluster_A"},
+			expError: `invalid policy name prefix "Cluster_A": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name:     "Invalid/LabelKey",
+			opts:     &MirrorOptions{PolicyLabels: map[string]string{"-team": "infra"}},
+			expError: `invalid policy label key "-team": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.validatePolicyOptions()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenerateAllICSPs(t *testing.T) {
+	mapping := image.TypedImageMapping{}
+	for _, m := range []struct {
+		src, dst string
+		typ      image.ImageType
+	}{
+		{
+			src: "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			dst: "mirror.io/openshift/release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			typ: image.TypeOCPRelease,
+		},
+		{
+			src: "registry.redhat.io/ubi8/ubi:latest@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002",
+			dst: "mirror.io/ubi8/ubi:latest@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a002",
+			typ: image.TypeGeneric,
+		},
+	} {
+		src, err := image.ParseTypedImage(m.src, m.typ)
+		require.NoError(t, err)
+		dst, err := image.ParseTypedImage(m.dst, m.typ)
+		require.NoError(t, err)
+		mapping[src] = dst
+	}
+
+	type spec struct {
+		name  string
+		opts  *MirrorOptions
+		files map[string]string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/PerCategory",
+			opts: &MirrorOptions{
+				PolicyScopes:     map[string]string{"generic": "registry"},
+				PolicyNamePrefix: "cluster-a",
+				PolicyLabels:     map[string]string{"team": "infra"},
+			},
+			files: map[string]string{
+				"imageContentSourcePolicy.yaml": `---
+apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  labels:
+    team: infra
+  name: cluster-a-generic-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - mirror.io
+    source: registry.redhat.io
+---
+apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  labels:
+    team: infra
+  name: cluster-a-release-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - mirror.io/openshift/release
+    source: quay.io/openshift-release-dev/ocp-release
+`,
+				"registries.conf": `# Generated by oc-mirror
+
+[[registry]]
+  prefix = ""
+  location = "quay.io/openshift-release-dev/ocp-release"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io/openshift/release"
+
+[[registry]]
+  prefix = ""
+  location = "registry.redhat.io"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io"
+`,
+				"installConfigImageSources.yaml": `imageContentSources:
+- mirrors:
+  - mirror.io/openshift/release
+  source: quay.io/openshift-release-dev/ocp-release
+- mirrors:
+  - mirror.io
+  source: registry.redhat.io
+`,
+				"machineConfigRegistries.yaml": `---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-cluster-a-mirror-registries
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,IyBHZW5lcmF0ZWQgYnkgb2MtbWlycm9yCgpbW3JlZ2lzdHJ5XV0KICBwcmVmaXggPSAiIgogIGxvY2F0aW9uID0gInF1YXkuaW8vb3BlbnNoaWZ0LXJlbGVhc2UtZGV2L29jcC1yZWxlYXNlIgogIG1pcnJvci1ieS1kaWdlc3Qtb25seSA9IHRydWUKCiAgW1tyZWdpc3RyeS5taXJyb3JdXQogICAgbG9jYXRpb24gPSAibWlycm9yLmlvL29wZW5zaGlmdC9yZWxlYXNlIgoKW1tyZWdpc3RyeV1dCiAgcHJlZml4ID0gIiIKICBsb2NhdGlvbiA9ICJyZWdpc3RyeS5yZWRoYXQuaW8iCiAgbWlycm9yLWJ5LWRpZ2VzdC1vbmx5ID0gdHJ1ZQoKICBbW3JlZ2lzdHJ5Lm1pcnJvcl1dCiAgICBsb2NhdGlvbiA9ICJtaXJyb3IuaW8iCg==
+        mode: 420
+        overwrite: true
+        path: /etc/containers/registries.conf.d/99-oc-mirror.conf
+---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  labels:
+    machineconfiguration.openshift.io/role: worker
+  name: 99-worker-cluster-a-mirror-registries
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,IyBHZW5lcmF0ZWQgYnkgb2MtbWlycm9yCgpbW3JlZ2lzdHJ5XV0KICBwcmVmaXggPSAiIgogIGxvY2F0aW9uID0gInF1YXkuaW8vb3BlbnNoaWZ0LXJlbGVhc2UtZGV2L29jcC1yZWxlYXNlIgogIG1pcnJvci1ieS1kaWdlc3Qtb25seSA9IHRydWUKCiAgW1tyZWdpc3RyeS5taXJyb3JdXQogICAgbG9jYXRpb24gPSAibWlycm9yLmlvL29wZW5zaGlmdC9yZWxlYXNlIgoKW1tyZWdpc3RyeV1dCiAgcHJlZml4ID0gIiIKICBsb2NhdGlvbiA9ICJyZWdpc3RyeS5yZWRoYXQuaW8iCiAgbWlycm9yLWJ5LWRpZ2VzdC1vbmx5ID0gdHJ1ZQoKICBbW3JlZ2lzdHJ5Lm1pcnJvcl1dCiAgICBsb2NhdGlvbiA9ICJtaXJyb3IuaW8iCg==
+        mode: 420
+        overwrite: true
+        path: /etc/containers/registries.conf.d/99-oc-mirror.conf
+`,
+			},
+		},
+		{
+			name: "Valid/Consolidated",
+			opts: &MirrorOptions{
+				PolicyFormat:        policyFormatMirrorSet,
+				ConsolidatePolicies: true,
+			},
+			files: map[string]string{
+				"imageDigestMirrorSet.yaml": `---
+apiVersion: config.openshift.io/v1
+kind: ImageDigestMirrorSet
+metadata:
+  name: mirror-0
+spec:
+  imageDigestMirrors:
+  - mirrors:
+    - mirror.io/openshift/release
+    source: quay.io/openshift-release-dev/ocp-release
+  - mirrors:
+    - mirror.io/ubi8
+    source: registry.redhat.io/ubi8
+`,
+				"imageTagMirrorSet.yaml": `---
+apiVersion: config.openshift.io/v1
+kind: ImageTagMirrorSet
+metadata:
+  name: mirror-0
+spec:
+  imageTagMirrors:
+  - mirrors:
+    - mirror.io/ubi8
+    source: registry.redhat.io/ubi8
+`,
+				"registries.conf": `# Generated by oc-mirror
+
+[[registry]]
+  prefix = ""
+  location = "quay.io/openshift-release-dev/ocp-release"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io/openshift/release"
+
+[[registry]]
+  prefix = ""
+  location = "registry.redhat.io/ubi8"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io/ubi8"
+`,
+				"installConfigImageSources.yaml": `imageDigestSources:
+- mirrors:
+  - mirror.io/openshift/release
+  source: quay.io/openshift-release-dev/ocp-release
+- mirrors:
+  - mirror.io/ubi8
+  source: registry.redhat.io/ubi8
+`,
+				"machineConfigRegistries.yaml": `---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-mirror-registries
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,IyBHZW5lcmF0ZWQgYnkgb2MtbWlycm9yCgpbW3JlZ2lzdHJ5XV0KICBwcmVmaXggPSAiIgogIGxvY2F0aW9uID0gInF1YXkuaW8vb3BlbnNoaWZ0LXJlbGVhc2UtZGV2L29jcC1yZWxlYXNlIgogIG1pcnJvci1ieS1kaWdlc3Qtb25seSA9IHRydWUKCiAgW1tyZWdpc3RyeS5taXJyb3JdXQogICAgbG9jYXRpb24gPSAibWlycm9yLmlvL29wZW5zaGlmdC9yZWxlYXNlIgoKW1tyZWdpc3RyeV1dCiAgcHJlZml4ID0gIiIKICBsb2NhdGlvbiA9ICJyZWdpc3RyeS5yZWRoYXQuaW8vdWJpOCIKICBtaXJyb3ItYnktZGlnZXN0LW9ubHkgPSB0cnVlCgogIFtbcmVnaXN0cnkubWlycm9yXV0KICAgIGxvY2F0aW9uID0gIm1pcnJvci5pby91Ymk4Igo=
+        mode: 420
+        overwrite: true
+        path: /etc/containers/registries.conf.d/99-oc-mirror.conf
+---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  labels:
+    machineconfiguration.openshift.io/role: worker
+  name: 99-worker-mirror-registries
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,IyBHZW5lcmF0ZWQgYnkgb2MtbWlycm9yCgpbW3JlZ2lzdHJ5XV0KICBwcmVmaXggPSAiIgogIGxvY2F0aW9uID0gInF1YXkuaW8vb3BlbnNoaWZ0LXJlbGVhc2UtZGV2L29jcC1yZWxlYXNlIgogIG1pcnJvci1ieS1kaWdlc3Qtb25seSA9IHRydWUKCiAgW1tyZWdpc3RyeS5taXJyb3JdXQogICAgbG9jYXRpb24gPSAibWlycm9yLmlvL29wZW5zaGlmdC9yZWxlYXNlIgoKW1tyZWdpc3RyeV1dCiAgcHJlZml4ID0gIiIKICBsb2NhdGlvbiA9ICJyZWdpc3RyeS5yZWRoYXQuaW8vdWJpOCIKICBtaXJyb3ItYnktZGlnZXN0LW9ubHkgPSB0cnVlCgogIFtbcmVnaXN0cnkubWlycm9yXV0KICAgIGxvY2F0aW9uID0gIm1pcnJvci5pby91Ymk4Igo=
+        mode: 420
+        overwrite: true
+        path: /etc/containers/registries.conf.d/99-oc-mirror.conf
+`,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, c.opts.generateAllICSPs(mapping, dir))
+
+			files, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			require.Len(t, files, len(c.files))
+			for name, exp := range c.files {
+				data, err := ioutil.ReadFile(filepath.Join(dir, name))
+				require.NoError(t, err)
+				require.Equal(t, exp, string(data))
+			}
+		})
+	}
+}
@@ -56,7 +56,7 @@ func (e *ErrArchiveFileNotFound) Error() string {
 }
 
 // Publish will plan a mirroring operation based on provided imageset on disk
-func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, error) {
+func (o *MirrorOptions) Publish(ctx context.Context) (v1alpha2.Metadata, image.TypedImageMapping, error) {
 
 	logrus.Infof("Publishing image set from archive %q to registry %q", o.From, o.ToMirror)
 
@@ -74,14 +74,14 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 		dir, err := o.createResultsDir()
 		o.OutputDir = dir
 		if err != nil {
-			return allMappings, err
+			return incomingMeta, allMappings, err
 		}
 	}
 
 	// Create workspace
 	cleanup, tmpdir, err := mktempDir(o.Dir)
 	if err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	}
 
 	// Handle cleanup of disk
@@ -94,22 +94,28 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 	// Get file information from the source archives
 	filesInArchive, err := bundle.ReadImageSet(a, o.From)
 	if err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
+	}
+
+	if !o.SkipCapacityCheck && !o.DryRun {
+		if err := o.checkPublishCapacity(); err != nil {
+			return incomingMeta, allMappings, err
+		}
 	}
 
 	// Extract imageset
 	if err := o.unpackImageSet(a, tmpdir); err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	}
 
 	// Create a local workspace backend for incoming data
 	workspace, err := storage.NewLocalBackend(tmpdir)
 	if err != nil {
-		return allMappings, fmt.Errorf("error opening local backend: %v", err)
+		return incomingMeta, allMappings, fmt.Errorf("error opening local backend: %v", err)
 	}
 	// Load incoming metadta
 	if err := workspace.ReadMetadata(ctx, &incomingMeta, config.MetadataBasePath); err != nil {
-		return allMappings, fmt.Errorf("error reading incoming metadata: %v", err)
+		return incomingMeta, allMappings, fmt.Errorf("error reading incoming metadata: %v", err)
 	}
 
 	metaImage := o.newMetadataImage(incomingMeta.Uid.String())
@@ -121,9 +127,12 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 			Local: &v1alpha2.LocalConfig{Path: o.Dir}}
 		backend, err = storage.ByConfig(o.Dir, cfg)
 		if err != nil {
-			return allMappings, err
+			return incomingMeta, allMappings, err
 		}
 		defer func() {
+			if o.DryRun {
+				return
+			}
 			if err := backend.Cleanup(ctx, config.MetadataBasePath); err != nil {
 				logrus.Error(err)
 			}
@@ -137,20 +146,20 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 		}
 		backend, err = storage.ByConfig(o.Dir, cfg)
 		if err != nil {
-			return allMappings, err
+			return incomingMeta, allMappings, err
 		}
 	}
 
 	// Read in current metadata, if present
 	switch err := backend.ReadMetadata(ctx, &currentMeta, config.MetadataBasePath); {
 	case err != nil && !errors.Is(err, storage.ErrMetadataNotExist):
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	case err != nil:
 		logrus.Infof("No existing metadata found. Setting up new workspace")
 		// Check that this is the first imageset
 		incomingRun := incomingMeta.PastMirror
 		if incomingRun.Sequence != 1 {
-			return allMappings, &SequenceError{1, incomingRun.Sequence}
+			return incomingMeta, allMappings, &SequenceError{1, incomingRun.Sequence}
 		}
 	default:
 		// Complete metadata checks
@@ -159,29 +168,45 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 		currRun := currentMeta.PastMirror
 		incomingRun := incomingMeta.PastMirror
 		if incomingRun.Sequence != (currRun.Sequence + 1) {
-			return allMappings, &SequenceError{currRun.Sequence + 1, incomingRun.Sequence}
+			return incomingMeta, allMappings, &SequenceError{currRun.Sequence + 1, incomingRun.Sequence}
 		}
 	}
+	if o.DryRun {
+		logrus.Infof("Dry run: would update metadata sequence from %d to %d",
+			currentMeta.PastMirror.Sequence, incomingMeta.PastMirror.Sequence)
+		logrus.Info("Dry run: would unpack any provided Helm charts, release signatures, and release tools")
+	} else {
+		// Unpack chart to user destination if it exists
+		logrus.Debugf("Unpacking any provided Helm charts to %s", o.OutputDir)
+		if err := unpack(config.HelmDir, o.OutputDir, filesInArchive); err != nil {
+			return incomingMeta, allMappings, err
+		}
+
+		// Stage release signatures so signature ConfigMaps can be written
+		if err := o.stageReleaseSignatures(tmpdir); err != nil {
+			return incomingMeta, allMappings, err
+		}
 
-	// Unpack chart to user destination if it exists
-	logrus.Debugf("Unpacking any provided Helm charts to %s", o.OutputDir)
-	if err := unpack(config.HelmDir, o.OutputDir, filesInArchive); err != nil {
-		return allMappings, err
+		// Move release tools to user destination if they exist
+		logrus.Debugf("Moving any extracted release tools to %s", o.OutputDir)
+		if err := moveReleaseTools(filepath.Join(tmpdir, config.ToolsDir), filepath.Join(o.OutputDir, config.ToolsDir)); err != nil {
+			return incomingMeta, allMappings, err
+		}
 	}
 
 	// Load image associations to find layers not present locally.
-	assocs, err := readAssociations(filepath.Join(tmpdir, config.AssociationsBasePath))
+	assocs, err := readAssociations(tmpdir)
 	if err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	}
 
 	toMirrorRef, err := imagesource.ParseReference(o.ToMirror)
 	if err != nil {
-		return allMappings, fmt.Errorf("error parsing mirror registry %q: %v", o.ToMirror, err)
+		return incomingMeta, allMappings, fmt.Errorf("error parsing mirror registry %q: %v", o.ToMirror, err)
 	}
 	logrus.Debugf("mirror reference: %#v", toMirrorRef)
 	if toMirrorRef.Type != imagesource.DestinationRegistry {
-		return allMappings, fmt.Errorf("destination %q must be a registry reference", o.ToMirror)
+		return incomingMeta, allMappings, fmt.Errorf("destination %q must be a registry reference", o.ToMirror)
 	}
 
 	var errs []error
@@ -195,7 +220,7 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 		// Create temp workspace for image processing
 		cleanUnpackDir, unpackDir, err := mktempDir(tmpdir)
 		if err != nil {
-			return allMappings, err
+			return incomingMeta, allMappings, err
 		}
 
 		for _, assoc := range values {
@@ -286,9 +311,13 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 			}
 
 			if len(missingLayers) != 0 {
+				if o.DryRun {
+					logrus.Infof("Dry run: would fetch %d layers for image %s from the mirror registry", len(missingLayers), imageName)
+					continue
+				}
 				// Fetch all layers and mount them at the specified paths.
 				if err := o.fetchBlobs(ctx, currentMeta, missingLayers); err != nil {
-					return allMappings, err
+					return incomingMeta, allMappings, err
 				}
 			}
 		}
@@ -306,37 +335,51 @@ func (o *MirrorOptions) Publish(ctx context.Context) (image.TypedImageMapping, e
 		}
 	}
 	if len(errs) != 0 {
-		return allMappings, utilerrors.NewAggregate(errs)
+		return incomingMeta, allMappings, utilerrors.NewAggregate(errs)
 	}
 
 	logrus.Debug("rebuilding catalog images")
 
 	found, err := o.unpackCatalog(tmpdir, filesInArchive)
 	if err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	}
 
 	if found {
 		ctlgRefs, err := o.rebuildCatalogs(ctx, tmpdir)
 		if err != nil {
-			return allMappings, fmt.Errorf("error rebuilding catalog images from file-based catalogs: %v", err)
+			return incomingMeta, allMappings, fmt.Errorf("error rebuilding catalog images from file-based catalogs: %v", err)
 		}
 		if err := WriteCatalogSource(ctlgRefs, o.OutputDir); err != nil {
-			return allMappings, err
+			return incomingMeta, allMappings, err
 		}
 		allMappings.Merge(ctlgRefs)
 	}
 
+	// Record published images so image mirror policies
+	// can be generated for all published sequences.
+	incomingMeta.MirroredImages = mergeMirroredImages(currentMeta.MirroredImages, allMappings)
+
+	if o.DryRun {
+		logrus.Infof("Dry run: would write metadata for sequence %d", incomingMeta.PastMirror.Sequence)
+		return incomingMeta, allMappings, nil
+	}
+
 	// Replace old metadata with new metadata
 	if err := backend.WriteMetadata(ctx, &incomingMeta, config.MetadataBasePath); err != nil {
-		return allMappings, err
+		return incomingMeta, allMappings, err
 	}
 
-	return allMappings, nil
+	return incomingMeta, allMappings, nil
 }
 
 // readAssociations will process and return data from the image associations file
-func readAssociations(assocPath string) (assocs image.AssociationSet, err error) {
+// in dir, falling back to the legacy file written by older versions.
+func readAssociations(dir string) (assocs image.AssociationSet, err error) {
+	assocPath := filepath.Join(dir, config.AssociationsBasePath)
+	if _, err := os.Stat(assocPath); errors.Is(err, os.ErrNotExist) {
+		assocPath = filepath.Join(dir, config.LegacyAssociationsBasePath)
+	}
 	f, err := os.Open(filepath.Clean(assocPath))
 	if err != nil {
 		return assocs, fmt.Errorf("error opening image associations file: %v", err)
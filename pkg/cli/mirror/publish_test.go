@@ -1,14 +1,18 @@
 package mirror
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/registry"
@@ -21,6 +25,7 @@ import (
 	"github.com/openshift/oc-mirror/pkg/cli"
 	"github.com/openshift/oc-mirror/pkg/config"
 	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
+	"github.com/openshift/oc-mirror/pkg/image"
 	"github.com/openshift/oc-mirror/pkg/metadata/storage"
 )
 
@@ -97,7 +102,7 @@ func TestMetadataError(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			_, err = opts.Publish(ctx)
+			_, _, err = opts.Publish(ctx)
 
 			if !tt.wantErr {
 				require.NoError(t, err)
@@ -191,6 +196,120 @@ func TestFindBlobRepo(t *testing.T) {
 	}
 }
 
+func TestPublishDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	var requests, writes int32
+	handler := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			atomic.AddInt32(&writes, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// Publish the second imageset on top of existing metadata.
+	tmpdir := t.TempDir()
+	input, err := ioutil.ReadFile("testdata/configs/one.json")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpdir, config.PublishDir), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, config.MetadataBasePath), input, 0644))
+	require.NoError(t, prepMetadata(ctx, u.Host, tmpdir, "360a43c2-8a14-4b5d-906b-07491459f25f"))
+
+	from := filepath.Join(t.TempDir(), "mirror_seq2_000000.tar")
+	writeDryRunImageSet(t, from)
+
+	opts := &MirrorOptions{
+		RootOptions: &cli.RootOptions{
+			IOStreams: genericclioptions.IOStreams{
+				In:     os.Stdin,
+				Out:    os.Stdout,
+				ErrOut: os.Stderr,
+			},
+			Dir: tmpdir,
+		},
+		DestSkipTLS: true,
+		DryRun:      true,
+		From:        from,
+		ToMirror:    u.Host,
+	}
+
+	before := workspaceFiles(t, tmpdir)
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&writes, 0)
+
+	require.NoError(t, opts.Validate())
+	require.Equal(t, int32(0), atomic.LoadInt32(&requests), "validate contacted the registry")
+
+	meta, _, err := opts.Publish(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, meta.PastMirror.Sequence)
+	require.Equal(t, int32(0), atomic.LoadInt32(&writes), "publish wrote to the registry")
+
+	require.NotContains(t, opts.OutputDir, tmpdir)
+	require.NoDirExists(t, filepath.Join(opts.OutputDir, config.HelmDir))
+	require.NoError(t, logDryRunResults(opts.OutputDir))
+	require.NoDirExists(t, opts.OutputDir)
+	require.Equal(t, before, workspaceFiles(t, tmpdir))
+}
+
+// writeDryRunImageSet writes an imageset archive with the metadata
+// of testbundle_seq2.tar, empty image associations, and a Helm chart.
+func writeDryRunImageSet(t *testing.T, path string) {
+	seq2, err := os.Open("testdata/artifacts/testbundle_seq2.tar")
+	require.NoError(t, err)
+	defer seq2.Close()
+	var meta []byte
+	tr := tar.NewReader(seq2)
+	for meta == nil {
+		header, err := tr.Next()
+		require.NoError(t, err)
+		if filepath.Clean(header.Name) == config.MetadataBasePath {
+			meta, err = ioutil.ReadAll(tr)
+			require.NoError(t, err)
+		}
+	}
+	var assocs bytes.Buffer
+	require.NoError(t, image.AssociationSet{}.Encode(&assocs))
+	chart, err := ioutil.ReadFile("testdata/artifacts/podinfo-6.0.0.tgz")
+	require.NoError(t, err)
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	for name, data := range map[string][]byte{
+		config.MetadataBasePath:                            meta,
+		config.AssociationsBasePath:                        assocs.Bytes(),
+		filepath.Join(config.HelmDir, "podinfo-6.0.0.tgz"): chart,
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+}
+
+// workspaceFiles returns the regular files under dir.
+func workspaceFiles(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.Walk(dir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, fpath)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
 // prepareMetadata will ensure metadata is in the registry for testing
 func prepMetadata(ctx context.Context, host, dir, uuid string) error {
 	var meta v1alpha2.Metadata
@@ -0,0 +1,168 @@
+package mirror
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	registriesConfFile = "registries.conf"
+	installConfigFile  = "installConfigImageSources.yaml"
+	machineConfigFile  = "machineConfigRegistries.yaml"
+	// registriesConfPath is the location of the
+	// registries.conf drop-in on cluster nodes.
+	registriesConfPath = "/etc/containers/registries.conf.d/99-oc-mirror.conf"
+	ignitionVersion    = "3.2.0"
+)
+
+// machineConfigRoles are the node roles a MachineConfig is generated for.
+var machineConfigRoles = []string{"master", "worker"}
+
+// mirrorSource is a source location and the locations mirroring it.
+type mirrorSource struct {
+	Mirrors []string `yaml:"mirrors"`
+	Source  string   `yaml:"source"`
+}
+
+// mirrorSources returns the source to mirror locations of all groups sorted by source.
+// Sources found in multiple groups are combined into a single entry.
+func mirrorSources(groups []policyGroup) []mirrorSource {
+	mirrorsBySource := map[string]map[string]struct{}{}
+	for _, group := range groups {
+		for source, mirror := range group.registryMapping {
+			if mirrorsBySource[source] == nil {
+				mirrorsBySource[source] = map[string]struct{}{}
+			}
+			mirrorsBySource[source][mirror] = struct{}{}
+		}
+	}
+
+	sources := make([]mirrorSource, 0, len(mirrorsBySource))
+	for source, mirrors := range mirrorsBySource {
+		ms := mirrorSource{Source: source}
+		for mirror := range mirrors {
+			ms.Mirrors = append(ms.Mirrors, mirror)
+		}
+		sort.Strings(ms.Mirrors)
+		sources = append(sources, ms)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Source < sources[j].Source
+	})
+	return sources
+}
+
+// generateRegistriesConf returns a containers-registries.conf(5) drop-in
+// that redirects pulls by digest from each source to its mirrors.
+func generateRegistriesConf(sources []mirrorSource) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by oc-mirror\n")
+	for _, s := range sources {
+		fmt.Fprintf(&buf, "\n[[registry]]\n  prefix = \"\"\n  location = %s\n  mirror-by-digest-only = true\n", strconv.Quote(s.Source))
+		for _, mirror := range s.Mirrors {
+			fmt.Fprintf(&buf, "\n  [[registry.mirror]]\n    location = %s\n", strconv.Quote(mirror))
+		}
+	}
+	return buf.Bytes()
+}
+
+// generateInstallConfigSources returns an install-config.yaml snippet
+// listing the mirrors for each source under key.
+func generateInstallConfigSources(key string, sources []mirrorSource) ([]byte, error) {
+	snippet, err := yaml.Marshal(map[string]interface{}{key: sources})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal install-config yaml: %v", err)
+	}
+	return snippet, nil
+}
+
+// generateMachineConfig returns a MachineConfig that writes
+// registriesConf to nodes with the given role.
+func generateMachineConfig(name, role string, registriesConf []byte) ([]byte, error) {
+	obj := map[string]interface{}{
+		"apiVersion": "machineconfiguration.openshift.io/v1",
+		"kind":       "MachineConfig",
+		"metadata": map[string]interface{}{
+			"name": name,
+			"labels": map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		"spec": map[string]interface{}{
+			"config": map[string]interface{}{
+				"ignition": map[string]interface{}{
+					"version": ignitionVersion,
+				},
+				"storage": map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{
+							"path":      registriesConfPath,
+							"mode":      0644,
+							"overwrite": true,
+							"contents": map[string]interface{}{
+								"source": "data:text/plain;charset=utf-8;base64," + base64.StdEncoding.EncodeToString(registriesConf),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	mc, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal MachineConfig yaml: %v", err)
+	}
+	return mc, nil
+}
+
+// writeRegistryConfigs writes the registries.conf drop-in, install-config.yaml
+// snippet, and MachineConfigs for the digest mirrors in groups to dir.
+func (o *MirrorOptions) writeRegistryConfigs(groups []policyGroup, dir string) error {
+	sources := mirrorSources(groups)
+	if len(sources) == 0 {
+		logrus.Debug("No mirrors to write to registries.conf")
+		return nil
+	}
+
+	registriesConf := generateRegistriesConf(sources)
+	if err := ioutil.WriteFile(filepath.Join(dir, registriesConfFile), registriesConf, os.ModePerm); err != nil {
+		return fmt.Errorf("error writing registries.conf: %v", err)
+	}
+
+	// The installer rejects configurations setting both fields,
+	// so the deprecated field is only used for ICSP output.
+	key := "imageDigestSources"
+	if o.PolicyFormat == "" || o.PolicyFormat == policyFormatICSP {
+		key = "imageContentSources"
+	}
+	installConfig, err := generateInstallConfigSources(key, sources)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, installConfigFile), installConfig, os.ModePerm); err != nil {
+		return fmt.Errorf("error writing install-config snippet: %v", err)
+	}
+
+	mcs := make([][]byte, len(machineConfigRoles))
+	for i, role := range machineConfigRoles {
+		name := fmt.Sprintf("99-%s-%s", role, o.policyName("mirror-registries"))
+		if mcs[i], err = generateMachineConfig(name, role, registriesConf); err != nil {
+			return err
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, machineConfigFile), aggregateICSPs(mcs), os.ModePerm); err != nil {
+		return fmt.Errorf("error writing MachineConfig: %v", err)
+	}
+
+	logrus.Infof("Wrote registries.conf, install-config snippet, and MachineConfig manifests to %s", dir)
+	return nil
+}
@@ -0,0 +1,142 @@
+package mirror
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestMirrorSources(t *testing.T) {
+	groups := []policyGroup{
+		{registryMapping: map[string]string{
+			"registry.redhat.io":      "mirror.io",
+			"quay.io/openshift/ocp":   "mirror.io/openshift/ocp",
+			"registry.redhat.io/ubi8": "mirror.io/ubi8",
+		}},
+		{registryMapping: map[string]string{
+			"registry.redhat.io": "mirror-b.io",
+		}},
+	}
+	exp := []mirrorSource{
+		{Source: "quay.io/openshift/ocp", Mirrors: []string{"mirror.io/openshift/ocp"}},
+		{Source: "registry.redhat.io", Mirrors: []string{"mirror-b.io", "mirror.io"}},
+		{Source: "registry.redhat.io/ubi8", Mirrors: []string{"mirror.io/ubi8"}},
+	}
+	require.Equal(t, exp, mirrorSources(groups))
+}
+
+func TestWriteRegistryConfigs(t *testing.T) {
+	groups := []policyGroup{
+		{registryMapping: map[string]string{"quay.io/openshift-release-dev/ocp-release": "mirror.io/openshift/release"}},
+		{registryMapping: map[string]string{"registry.redhat.io/ubi8": "mirror.io/ubi8"}},
+	}
+
+	expRegistriesConf := `# Generated by oc-mirror
+
+[[registry]]
+  prefix = ""
+  location = "quay.io/openshift-release-dev/ocp-release"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io/openshift/release"
+
+[[registry]]
+  prefix = ""
+  location = "registry.redhat.io/ubi8"
+  mirror-by-digest-only = true
+
+  [[registry.mirror]]
+    location = "mirror.io/ubi8"
+`
+
+	type spec struct {
+		name             string
+		opts             *MirrorOptions
+		expInstallConfig string
+		expMCNames       []string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/ICSPFormat",
+			opts: &MirrorOptions{},
+			expInstallConfig: `imageContentSources:
+- mirrors:
+  - mirror.io/openshift/release
+  source: quay.io/openshift-release-dev/ocp-release
+- mirrors:
+  - mirror.io/ubi8
+  source: registry.redhat.io/ubi8
+`,
+			expMCNames: []string{"99-master-mirror-registries", "99-worker-mirror-registries"},
+		},
+		{
+			name: "Valid/MirrorSetFormat",
+			opts: &MirrorOptions{PolicyFormat: policyFormatMirrorSet, PolicyNamePrefix: "cluster-a"},
+			expInstallConfig: `imageDigestSources:
+- mirrors:
+  - mirror.io/openshift/release
+  source: quay.io/openshift-release-dev/ocp-release
+- mirrors:
+  - mirror.io/ubi8
+  source: registry.redhat.io/ubi8
+`,
+			expMCNames: []string{"99-master-cluster-a-mirror-registries", "99-worker-cluster-a-mirror-registries"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, c.opts.writeRegistryConfigs(groups, dir))
+
+			data, err := ioutil.ReadFile(filepath.Join(dir, registriesConfFile))
+			require.NoError(t, err)
+			require.Equal(t, expRegistriesConf, string(data))
+
+			data, err = ioutil.ReadFile(filepath.Join(dir, installConfigFile))
+			require.NoError(t, err)
+			require.Equal(t, c.expInstallConfig, string(data))
+
+			data, err = ioutil.ReadFile(filepath.Join(dir, machineConfigFile))
+			require.NoError(t, err)
+			docs := strings.Split(strings.TrimPrefix(string(data), "---\n"), "---\n")
+			require.Len(t, docs, len(c.expMCNames))
+			for i, doc := range docs {
+				var mc struct {
+					Metadata struct {
+						Name   string            `yaml:"name"`
+						Labels map[string]string `yaml:"labels"`
+					} `yaml:"metadata"`
+					Spec struct {
+						Config struct {
+							Storage struct {
+								Files []struct {
+									Path     string `yaml:"path"`
+									Contents struct {
+										Source string `yaml:"source"`
+									} `yaml:"contents"`
+								} `yaml:"files"`
+							} `yaml:"storage"`
+						} `yaml:"config"`
+					} `yaml:"spec"`
+				}
+				require.NoError(t, yaml.Unmarshal([]byte(doc), &mc))
+				require.Equal(t, c.expMCNames[i], mc.Metadata.Name)
+				require.Equal(t, machineConfigRoles[i], mc.Metadata.Labels["machineconfiguration.openshift.io/role"])
+				require.Len(t, mc.Spec.Config.Storage.Files, 1)
+				file := mc.Spec.Config.Storage.Files[0]
+				require.Equal(t, registriesConfPath, file.Path)
+				contents, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(file.Contents.Source, "data:text/plain;charset=utf-8;base64,"))
+				require.NoError(t, err)
+				require.Equal(t, expRegistriesConf, string(contents))
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package mirror
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"text/tabwriter"
 
 	semver "github.com/blang/semver/v4"
 	"github.com/google/uuid"
@@ -31,6 +35,22 @@ type ReleaseOptions struct {
 	// registry is insecure
 	insecure bool
 	uuid     uuid.UUID
+	// releases are the releases planned from release
+	// channels and the releases configured by pullspec
+	releases []v1alpha2.ReleaseMetadata
+	// added and removed are the resolved releases that
+	// differ from the releases resolved by the last run
+	added   []v1alpha2.ReleaseMetadata
+	removed []v1alpha2.ReleaseMetadata
+	// prunable are the releases that fell out
+	// of release channel retention windows
+	prunable []v1alpha2.ReleaseMetadata
+	// conditional are the releases planned on conditional
+	// update edges, by release image
+	conditional map[string]v1alpha2.ConditionalRelease
+	// paths are the upgrade paths planned for release
+	// channels with the shortest path strategy
+	paths []v1alpha2.UpgradePath
 }
 
 // NewReleaseOptions defaults ReleaseOptions.
@@ -74,7 +94,27 @@ func (o *ReleaseOptions) Plan(ctx context.Context, lastRun v1alpha2.PastMirror,
 				continue
 			}
 
-			if len(ch.MaxVersion) == 0 || len(ch.MinVersion) == 0 {
+			if len(ch.VersionConstraint) != 0 {
+				// Set the channel minimum and maximum to the bounds of the
+				// matched releases to plan upgrades between channels
+				updates, err := cincinnati.GetReleases(ctx, client, arch, ch.Name)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				matched, err := channelReleases(updates, ch)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if len(matched) == 0 {
+					errs = append(errs, fmt.Errorf("no releases in channel %s match version constraint %q", ch.Name, ch.VersionConstraint))
+					continue
+				}
+				ch.MinVersion = matched[0].Version.String()
+				ch.MaxVersion = matched[len(matched)-1].Version.String()
+				versionsByChannel[ch.Name] = ch
+			} else if len(ch.MaxVersion) == 0 || len(ch.MinVersion) == 0 {
 
 				// Find channel maximum value and only set the minimum as well if heads-only is true
 				if len(ch.MaxVersion) == 0 {
@@ -117,7 +157,7 @@ func (o *ReleaseOptions) Plan(ctx context.Context, lastRun v1alpha2.PastMirror,
 		cfg.Mirror.OCP.Channels = updateReleaseChannel(cfg.Mirror.OCP.Channels, versionsByChannel)
 
 		if len(cfg.Mirror.OCP.Channels) > 1 {
-			newDownloads, err := o.getCrossChannelDownloads(ctx, arch, cfg.Mirror.OCP.Channels)
+			newDownloads, err := o.getCrossChannelDownloads(ctx, arch, cfg.Mirror.OCP.Channels, cfg.Mirror.OCP.EUSUpgrade, lastRun)
 			if err != nil {
 				errs = append(errs, err)
 				continue
@@ -128,8 +168,46 @@ func (o *ReleaseOptions) Plan(ctx context.Context, lastRun v1alpha2.PastMirror,
 	if len(errs) != 0 {
 		return mmapping, utilerrors.NewAggregate(errs)
 	}
+	releaseDownloads.Merge(o.getExplicitDownloads(cfg.Mirror.OCP.Releases))
 
+	releases := make([]string, 0, len(releaseDownloads))
+	// Signatures are only published for releases referenced by digest
+	signed := make([]string, 0, len(releaseDownloads))
 	for img := range releaseDownloads {
+		releases = append(releases, img)
+		if _, err := releaseDigest(img); err != nil {
+			logrus.Warnf("Skipping signature collection for release %s: not referenced by digest", img)
+			continue
+		}
+		signed = append(signed, img)
+	}
+	sort.Strings(releases)
+	sort.Strings(signed)
+
+	o.added, o.removed = diffReleases(lastRun.Releases, o.releases)
+	if len(o.added) != 0 || len(o.removed) != 0 {
+		logrus.Infof("%d releases were added to and %d releases were removed from resolved release sets since the last run", len(o.added), len(o.removed))
+	}
+
+	o.prunable = prunableReleases(releaseDownloads, lastRun.Releases, lastRun.PrunableReleases)
+	if len(o.prunable) != 0 {
+		logrus.Infof("%d releases fell out of release channel retention windows and are eligible for pruning", len(o.prunable))
+	}
+
+	for _, rel := range o.conditionalReleases() {
+		for _, risk := range rel.Risks {
+			logrus.Warnf("release %s is planned on a conditional update with risk %s: %s", rel.Version, risk.Name, risk.URL)
+		}
+	}
+
+	if err := o.collectReleaseSignatures(ctx, signed); err != nil {
+		return mmapping, err
+	}
+	if err := o.verifyReleaseSignatures(ctx, releases); err != nil {
+		return mmapping, err
+	}
+
+	for _, img := range releases {
 		logrus.Debugf("Starting release download for version %s", img)
 		opts, err := o.newMirrorReleaseOptions(srcDir)
 		if err != nil {
@@ -146,6 +224,14 @@ func (o *ReleaseOptions) Plan(ctx context.Context, lastRun v1alpha2.PastMirror,
 		mmapping.Merge(mappings)
 	}
 
+	if o.IncludeTools {
+		if o.DryRun {
+			logrus.Infof("Dry run: would extract release tools for %d releases", len(releases))
+		} else if err := o.extractReleaseTools(releases); err != nil {
+			return mmapping, err
+		}
+	}
+
 	return mmapping, nil
 }
 
@@ -160,65 +246,183 @@ func (o *ReleaseOptions) getChannelDownloads(ctx context.Context, c cincinnati.C
 		}
 	}
 
+	switch {
+	case channel.KeepLatest > 0:
+		return o.getRetainedDownloads(ctx, c, channel, arch)
+	case channel.PathStrategy == v1alpha2.PathStrategyShortest:
+		return o.getShortestPathDownloads(ctx, c, channel, arch)
+	case channel.PathStrategy == v1alpha2.PathStrategyAll, channel.VersionConstraint != "":
+		return o.getRangeDownloads(ctx, c, channel, arch)
+	}
+
+	first, err := semver.Parse(channel.MinVersion)
+	if err != nil {
+		return allDownloads, err
+	}
+	last, err := semver.Parse(channel.MaxVersion)
+	if err != nil {
+		return allDownloads, err
+	}
+
 	if prevChannel.Name != "" {
+		prevFirst, err := semver.Parse(prevChannel.MinVersion)
+		if err != nil {
+			return allDownloads, err
+		}
+		prevLast, err := semver.Parse(prevChannel.MaxVersion)
+		if err != nil {
+			return allDownloads, err
+		}
+
 		// If the requested min version is less than the previous, add downloads
-		if prevChannel.MinVersion > channel.MinVersion {
-			first, err := semver.Parse(channel.MinVersion)
-			if err != nil {
-				return allDownloads, err
-			}
-			last, err := semver.Parse(prevChannel.MinVersion)
-			if err != nil {
-				return allDownloads, err
-			}
-			current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, last)
+		if first.LT(prevFirst) {
+			current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, prevFirst, cincinnati.RiskPolicy(channel))
 			if err != nil {
 				return allDownloads, err
 			}
-			newDownloads := gatherUpdates(current, newest, updates)
+			o.recordConditionalUpdates(updates)
+			newDownloads := o.gatherUpdates(channel.Name, current, newest, updates)
 			allDownloads.Merge(newDownloads)
 		}
 
 		// If the requested max version is more than the previous, add downloads
-		if prevChannel.MaxVersion < channel.MaxVersion {
-			first, err := semver.Parse(prevChannel.MaxVersion)
-			if err != nil {
-				return allDownloads, err
-			}
-			last, err := semver.Parse(channel.MinVersion)
-			if err != nil {
-				return allDownloads, err
-			}
-			current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, last)
+		if prevLast.LT(last) {
+			current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, prevLast, last, cincinnati.RiskPolicy(channel))
 			if err != nil {
 				return allDownloads, err
 			}
-			newDownloads := gatherUpdates(current, newest, updates)
+			o.recordConditionalUpdates(updates)
+			newDownloads := o.gatherUpdates(channel.Name, current, newest, updates)
 			allDownloads.Merge(newDownloads)
 		}
 	}
 
 	// Plot between min and max of channel
-	first, err := semver.Parse(channel.MinVersion)
+	current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, last, cincinnati.RiskPolicy(channel))
+	if err != nil {
+		return allDownloads, err
+	}
+	o.recordConditionalUpdates(updates)
+	newDownloads := o.gatherUpdates(channel.Name, current, newest, updates)
+	allDownloads.Merge(newDownloads)
+
+	return allDownloads, nil
+}
+
+// getRetainedDownloads will prepare the downloads map for a channel with a retention
+// policy by keeping the latest releases of each minor version between the channel
+// minimum and maximum versions
+func (o *ReleaseOptions) getRetainedDownloads(ctx context.Context, c cincinnati.Client, channel v1alpha2.ReleaseChannel, arch string) (downloads, error) {
+	allDownloads := downloads{}
+
+	updates, err := cincinnati.GetReleases(ctx, c, arch, channel.Name)
+	if err != nil {
+		return allDownloads, err
+	}
+	resolved, err := channelReleases(updates, channel)
 	if err != nil {
 		return allDownloads, err
 	}
+
+	for _, update := range retainLatest(resolved, channel.KeepLatest) {
+		allDownloads[update.Image] = struct{}{}
+		o.recordRelease(channel.Name, update)
+	}
+
+	return allDownloads, nil
+}
+
+// getShortestPathDownloads will prepare the downloads map for a channel with the
+// shortest path strategy by planning only the releases on the shortest upgrade path
+// from each starting version to the channel maximum version
+func (o *ReleaseOptions) getShortestPathDownloads(ctx context.Context, c cincinnati.Client, channel v1alpha2.ReleaseChannel, arch string) (downloads, error) {
+	allDownloads := downloads{}
+
 	last, err := semver.Parse(channel.MaxVersion)
 	if err != nil {
 		return allDownloads, err
 	}
-	current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, last)
+	starts := append([]string{channel.MinVersion}, channel.StartingVersions...)
+	seen := make(map[string]struct{}, len(starts))
+	for _, start := range starts {
+		if _, found := seen[start]; found {
+			continue
+		}
+		seen[start] = struct{}{}
+		first, err := semver.Parse(start)
+		if err != nil {
+			return allDownloads, err
+		}
+		current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, c, arch, channel.Name, channel.Name, first, last, cincinnati.RiskPolicy(channel))
+		if err != nil {
+			return allDownloads, err
+		}
+		if len(updates) == 0 {
+			logrus.Warnf("No upgrade path from %s to %s in channel %s", first, last, channel.Name)
+		} else {
+			o.recordUpgradePath(channel.Name, updates)
+		}
+		o.recordConditionalUpdates(updates)
+		allDownloads.Merge(o.gatherUpdates(channel.Name, current, newest, updates))
+	}
+
+	return allDownloads, nil
+}
+
+// getRangeDownloads will prepare the downloads map for a channel with the
+// all path strategy or a version constraint by planning every release matched
+// by the constraint or between the channel minimum and maximum versions
+func (o *ReleaseOptions) getRangeDownloads(ctx context.Context, c cincinnati.Client, channel v1alpha2.ReleaseChannel, arch string) (downloads, error) {
+	allDownloads := downloads{}
+
+	updates, err := cincinnati.GetReleases(ctx, c, arch, channel.Name)
 	if err != nil {
 		return allDownloads, err
 	}
-	newDownloads := gatherUpdates(current, newest, updates)
-	allDownloads.Merge(newDownloads)
+	resolved, err := channelReleases(updates, channel)
+	if err != nil {
+		return allDownloads, err
+	}
+	for _, update := range resolved {
+		allDownloads[update.Image] = struct{}{}
+		o.recordRelease(channel.Name, update)
+	}
 
 	return allDownloads, nil
 }
 
+// getExplicitDownloads will prepare the downloads map for the releases
+// configured by pullspec, which are mirrored without querying Cincinnati
+func (o *ReleaseOptions) getExplicitDownloads(releases []v1alpha2.ReleaseImage) downloads {
+	allDownloads := downloads{}
+	for _, rel := range releases {
+		if _, found := allDownloads[rel.Name]; found {
+			continue
+		}
+		allDownloads[rel.Name] = struct{}{}
+		o.releases = append(o.releases, v1alpha2.ReleaseMetadata{Image: rel.Name})
+	}
+	return allDownloads
+}
+
+// recordUpgradePath records the versions of a planned upgrade path.
+// Paths already recorded for the channel, such as the same path
+// for another architecture, are not recorded again.
+func (o *ReleaseOptions) recordUpgradePath(channel string, updates []cincinnati.Update) {
+	path := v1alpha2.UpgradePath{Channel: channel}
+	for _, update := range updates {
+		path.Versions = append(path.Versions, update.Version.String())
+	}
+	for _, recorded := range o.paths {
+		if reflect.DeepEqual(recorded, path) {
+			return
+		}
+	}
+	o.paths = append(o.paths, path)
+}
+
 // getCrossChannelDownloads will determine required downloads between channel versions (for OCP only)
-func (o *ReleaseOptions) getCrossChannelDownloads(ctx context.Context, arch string, channels []v1alpha2.ReleaseChannel) (downloads, error) {
+func (o *ReleaseOptions) getCrossChannelDownloads(ctx context.Context, arch string, channels []v1alpha2.ReleaseChannel, eus *v1alpha2.EUSUpgrade, lastRun v1alpha2.PastMirror) (downloads, error) {
 	// Strip any OKD channels from the list
 	ocpChannels := make([]v1alpha2.ReleaseChannel, len(channels))
 	copy(ocpChannels, channels)
@@ -244,24 +448,278 @@ func (o *ReleaseOptions) getCrossChannelDownloads(ctx context.Context, arch stri
 	if err != nil {
 		return downloads{}, fmt.Errorf("failed to find maximum release version: %v", err)
 	}
-	current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, client, arch, firstCh, lastCh, first, last)
+	// Conditional update edges are followed according
+	// to the policy of the target channel
+	var target v1alpha2.ReleaseChannel
+	for _, ch := range ocpChannels {
+		if ch.Name == lastCh {
+			target = ch
+		}
+	}
+	if eus != nil {
+		return o.getEUSDownloads(ctx, client, arch, firstCh, lastCh, first, last, target, eus, lastRun)
+	}
+	current, newest, updates, err := cincinnati.CalculateUpgrades(ctx, client, arch, firstCh, lastCh, first, last, cincinnati.RiskPolicy(target))
 	if err != nil {
 		return downloads{}, fmt.Errorf("failed to get upgrade graph: %v", err)
 	}
-	return gatherUpdates(current, newest, updates), nil
+	o.recordConditionalUpdates(updates)
+	return o.gatherUpdates(lastCh, current, newest, updates), nil
+}
+
+// getEUSDownloads will determine the required downloads on the shortest upgrade path
+// between two EUS channels. Releases on the upgrade path recorded by the last run are
+// preferred so the intermediate releases stay the same across runs where possible.
+func (o *ReleaseOptions) getEUSDownloads(ctx context.Context, c cincinnati.Client, arch, firstCh, lastCh string, first, last semver.Version, target v1alpha2.ReleaseChannel, eus *v1alpha2.EUSUpgrade, lastRun v1alpha2.PastMirror) (downloads, error) {
+	var preferred []string
+	for _, path := range lastRun.UpgradePaths {
+		if path.Channel == lastCh {
+			preferred = append(preferred, path.Versions...)
+		}
+	}
+	current, newest, updates, err := cincinnati.CalculateEUSUpgrades(ctx, c, arch, firstCh, lastCh, eus.IntermediatePrefix(),
+		first, last, preferred, cincinnati.RiskPolicy(target))
+	if err != nil {
+		return downloads{}, fmt.Errorf("failed to get EUS upgrade graph: %v", err)
+	}
+	if len(updates) == 0 {
+		logrus.Warnf("No upgrade path from %s in channel %s to %s in channel %s", first, firstCh, last, lastCh)
+	} else {
+		o.recordUpgradePath(lastCh, updates)
+	}
+	o.recordConditionalUpdates(updates)
+	return o.gatherUpdates(lastCh, current, newest, updates), nil
+}
+
+// recordConditionalUpdates records the updates reached by conditional update edges
+func (o *ReleaseOptions) recordConditionalUpdates(updates []cincinnati.Update) {
+	for _, update := range updates {
+		if len(update.Risks) == 0 {
+			continue
+		}
+		rel := v1alpha2.ConditionalRelease{
+			Version: update.Version.String(),
+			Image:   update.Image,
+		}
+		for _, risk := range update.Risks {
+			updateRisk := v1alpha2.UpdateRisk{
+				Name:    risk.Name,
+				URL:     risk.URL,
+				Message: risk.Message,
+			}
+			for _, rule := range risk.MatchingRules {
+				if rule.PromQL != nil {
+					updateRisk.PromQL = append(updateRisk.PromQL, rule.PromQL.PromQL)
+				}
+			}
+			rel.Risks = append(rel.Risks, updateRisk)
+		}
+		if o.conditional == nil {
+			o.conditional = map[string]v1alpha2.ConditionalRelease{}
+		}
+		o.conditional[update.Image] = rel
+	}
+}
+
+// conditionalReleases returns the releases planned on
+// conditional update edges, sorted by version and image
+func (o *ReleaseOptions) conditionalReleases() []v1alpha2.ConditionalRelease {
+	var releases []v1alpha2.ConditionalRelease
+	for _, rel := range o.conditional {
+		releases = append(releases, rel)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		vi, vj := semver.MustParse(releases[i].Version), semver.MustParse(releases[j].Version)
+		if !vi.EQ(vj) {
+			return vi.LT(vj)
+		}
+		return releases[i].Image < releases[j].Image
+	})
+	return releases
+}
+
+// writeConditionalReleases prints the releases planned on
+// conditional update edges and the risks of each release.
+func writeConditionalReleases(w io.Writer, releases []v1alpha2.ConditionalRelease) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprint(w, "\nReleases planned on conditional updates:\n"); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "VERSION\tRISK\tURL"); err != nil {
+		return err
+	}
+	for _, rel := range releases {
+		for _, risk := range rel.Risks {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", rel.Version, risk.Name, risk.URL); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
 }
 
-func gatherUpdates(current, newest cincinnati.Update, updates []cincinnati.Update) downloads {
+// gatherUpdates returns the downloads for the current and newest releases
+// and the updates between them, recording each release as resolved from channel
+func (o *ReleaseOptions) gatherUpdates(channel string, current, newest cincinnati.Update, updates []cincinnati.Update) downloads {
 	releaseDownloads := downloads{}
 	for _, update := range updates {
 		releaseDownloads[update.Image] = struct{}{}
+		o.recordRelease(channel, update)
 	}
 
 	releaseDownloads[current.Image] = struct{}{}
 	releaseDownloads[newest.Image] = struct{}{}
+	o.recordRelease(channel, current)
+	o.recordRelease(channel, newest)
 	return releaseDownloads
 }
 
+// recordRelease records a release resolved from channel. Releases
+// already recorded for the channel are not recorded again.
+func (o *ReleaseOptions) recordRelease(channel string, update cincinnati.Update) {
+	if update.Image == "" {
+		return
+	}
+	for _, rel := range o.releases {
+		if rel.Channel == channel && rel.Image == update.Image {
+			return
+		}
+	}
+	o.releases = append(o.releases, releaseMetadata(channel, update))
+}
+
+// versionRange returns the updates with versions between first and last, inclusive
+func versionRange(updates []cincinnati.Update, first, last semver.Version) []cincinnati.Update {
+	var inRange []cincinnati.Update
+	for _, update := range updates {
+		if update.Version.GTE(first) && update.Version.LTE(last) {
+			inRange = append(inRange, update)
+		}
+	}
+	return inRange
+}
+
+// channelReleases returns the updates matched by the version constraint of
+// the channel, or the updates between its minimum and maximum versions
+func channelReleases(updates []cincinnati.Update, channel v1alpha2.ReleaseChannel) ([]cincinnati.Update, error) {
+	if channel.VersionConstraint != "" {
+		matches, err := channel.VersionRange()
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint for channel %s: %v", channel.Name, err)
+		}
+		var matched []cincinnati.Update
+		for _, update := range updates {
+			if matches(update.Version) {
+				matched = append(matched, update)
+			}
+		}
+		return matched, nil
+	}
+
+	first, err := semver.Parse(channel.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	last, err := semver.Parse(channel.MaxVersion)
+	if err != nil {
+		return nil, err
+	}
+	return versionRange(updates, first, last), nil
+}
+
+// retainLatest returns the latest keep updates of each minor version.
+// Updates must be sorted by version.
+func retainLatest(updates []cincinnati.Update, keep int) []cincinnati.Update {
+	byMinor := make(map[string][]cincinnati.Update)
+	var minors []string
+	for _, update := range updates {
+		minor := fmt.Sprintf("%d.%d", update.Version.Major, update.Version.Minor)
+		if _, found := byMinor[minor]; !found {
+			minors = append(minors, minor)
+		}
+		byMinor[minor] = append(byMinor[minor], update)
+	}
+
+	var retained []cincinnati.Update
+	for _, minor := range minors {
+		updates := byMinor[minor]
+		if len(updates) > keep {
+			updates = updates[len(updates)-keep:]
+		}
+		retained = append(retained, updates...)
+	}
+	return retained
+}
+
+// prunableReleases returns the releases in past that are not planned
+// for download, sorted by channel and image. Past releases are the releases
+// recorded by the last run and the releases that were already prunable, so a
+// release stays prunable until it is planned again. Images are never removed
+// from the mirror, so the list is bounded by the releases ever mirrored.
+func prunableReleases(planned downloads, past ...[]v1alpha2.ReleaseMetadata) []v1alpha2.ReleaseMetadata {
+	seen := make(map[string]struct{})
+	var prunable []v1alpha2.ReleaseMetadata
+	for _, releases := range past {
+		for _, rel := range releases {
+			if _, found := planned[rel.Image]; found {
+				continue
+			}
+			if _, found := seen[rel.Image]; found {
+				continue
+			}
+			seen[rel.Image] = struct{}{}
+			prunable = append(prunable, rel)
+		}
+	}
+	sort.Slice(prunable, func(i, j int) bool {
+		if prunable[i].Channel != prunable[j].Channel {
+			return prunable[i].Channel < prunable[j].Channel
+		}
+		return prunable[i].Image < prunable[j].Image
+	})
+	return prunable
+}
+
+// diffReleases returns the releases in current that are not in last and
+// the releases in last that are not in current, sorted by channel and image
+func diffReleases(last, current []v1alpha2.ReleaseMetadata) (added, removed []v1alpha2.ReleaseMetadata) {
+	key := func(rel v1alpha2.ReleaseMetadata) string {
+		return rel.Channel + "/" + rel.Image
+	}
+	difference := func(from, to []v1alpha2.ReleaseMetadata) []v1alpha2.ReleaseMetadata {
+		seen := make(map[string]struct{}, len(to))
+		for _, rel := range to {
+			seen[key(rel)] = struct{}{}
+		}
+		var diff []v1alpha2.ReleaseMetadata
+		for _, rel := range from {
+			if _, found := seen[key(rel)]; !found {
+				seen[key(rel)] = struct{}{}
+				diff = append(diff, rel)
+			}
+		}
+		sort.Slice(diff, func(i, j int) bool {
+			if diff[i].Channel != diff[j].Channel {
+				return diff[i].Channel < diff[j].Channel
+			}
+			return diff[i].Image < diff[j].Image
+		})
+		return diff
+	}
+	return difference(current, last), difference(last, current)
+}
+
+func releaseMetadata(channel string, update cincinnati.Update) v1alpha2.ReleaseMetadata {
+	return v1alpha2.ReleaseMetadata{
+		Channel: channel,
+		Version: update.Version.String(),
+		Image:   update.Image,
+	}
+}
+
 func (o *ReleaseOptions) newMirrorReleaseOptions(fileDir string) (*release.MirrorOptions, error) {
 	opts := release.NewMirrorOptions(o.IOStreams)
 	opts.DryRun = o.DryRun
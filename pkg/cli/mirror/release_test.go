@@ -1,12 +1,14 @@
 package mirror
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	semver "github.com/blang/semver/v4"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
@@ -61,6 +63,70 @@ func TestGetDownloads(t *testing.T) {
 			"quay.io/openshift-release-dev/ocp-release:4.0.0-6":         struct{}{},
 			"quay.io/openshift-release-dev/ocp-release:4.0.0-6-another": struct{}{},
 		},
+	}, {
+		name: "Success/KeepLatest",
+		arch: []string{"test-arch"},
+		channels: []v1alpha2.ReleaseChannel{
+			{
+				Name:       "stable-4.0",
+				MinVersion: "4.0.0-4",
+				MaxVersion: "4.0.0-6",
+				KeepLatest: 2,
+			},
+		},
+		expected: downloads{
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-5": struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-6": struct{}{},
+		},
+	}, {
+		name: "Success/PathStrategyAll",
+		arch: []string{"test-arch"},
+		channels: []v1alpha2.ReleaseChannel{
+			{
+				Name:         "stable-4.0",
+				MinVersion:   "4.0.0-0.3",
+				MaxVersion:   "4.0.0-5",
+				PathStrategy: v1alpha2.PathStrategyAll,
+			},
+		},
+		expected: downloads{
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-0.3":     struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-0.okd-0": struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-4":       struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-5":       struct{}{},
+		},
+	}, {
+		name: "Success/PathStrategyShortest",
+		arch: []string{"test-arch"},
+		channels: []v1alpha2.ReleaseChannel{
+			{
+				Name:             "stable-4.0",
+				MinVersion:       "4.0.0-5",
+				MaxVersion:       "4.0.0-6",
+				PathStrategy:     v1alpha2.PathStrategyShortest,
+				StartingVersions: []string{"4.0.0-0.2"},
+			},
+		},
+		expected: downloads{
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-0.2": struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-5":   struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-6":   struct{}{},
+		},
+	}, {
+		name: "Success/VersionConstraint",
+		arch: []string{"test-arch"},
+		channels: []v1alpha2.ReleaseChannel{
+			{
+				Name:              "stable-4.0",
+				VersionConstraint: ">=4.0.0-0.3 <=4.0.0-5",
+				ExcludeVersions:   []string{"4.0.0-4"},
+			},
+		},
+		expected: downloads{
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-0.3":     struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-0.okd-0": struct{}{},
+			"quay.io/openshift-release-dev/ocp-release:4.0.0-5":       struct{}{},
+		},
 	}, {
 		name: "Failure/VersionStringEmpty",
 		channels: []v1alpha2.ReleaseChannel{
@@ -110,6 +176,198 @@ func TestGetDownloads(t *testing.T) {
 	}
 }
 
+func TestGetRetainedDownloads(t *testing.T) {
+	requestQuery := make(chan string, 10)
+	defer close(requestQuery)
+	ts := httptest.NewServer(http.HandlerFunc(getHandlerMulti(t, requestQuery)))
+	t.Cleanup(ts.Close)
+	endpoint, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	c := &mockClient{url: endpoint}
+
+	prevChannel := v1alpha2.ReleaseChannel{
+		Name:       "stable-4.0",
+		MinVersion: "4.0.0-4",
+		MaxVersion: "4.0.0-6",
+	}
+	channel := prevChannel
+	channel.MinVersion = "4.0.0-5"
+	channel.KeepLatest = 1
+
+	opts := ReleaseOptions{}
+	dl, err := opts.getChannelDownloads(context.Background(), c, []v1alpha2.ReleaseChannel{prevChannel}, channel, "test-arch")
+	require.NoError(t, err)
+	require.Equal(t, downloads{"quay.io/openshift-release-dev/ocp-release:4.0.0-6": struct{}{}}, dl)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-6", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-6"},
+	}, opts.releases)
+
+	// Planning the channel again does not record its releases twice.
+	_, err = opts.getChannelDownloads(context.Background(), c, []v1alpha2.ReleaseChannel{prevChannel}, channel, "test-arch")
+	require.NoError(t, err)
+	require.Len(t, opts.releases, 1)
+
+	// Releases recorded by the last run that are not retained become
+	// prunable, and releases that were already prunable stay prunable.
+	lastReleases := []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-4", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-4"},
+		{Channel: "stable-4.0", Version: "4.0.0-5", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-5"},
+		{Channel: "stable-4.0", Version: "4.0.0-6", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-6"},
+	}
+	lastPrunable := []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-0.2", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-0.2"},
+	}
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-0.2", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-0.2"},
+		{Channel: "stable-4.0", Version: "4.0.0-4", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-4"},
+		{Channel: "stable-4.0", Version: "4.0.0-5", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-5"},
+	}, prunableReleases(dl, lastReleases, lastPrunable))
+}
+
+func TestGetShortestPathDownloads(t *testing.T) {
+	requestQuery := make(chan string, 10)
+	defer close(requestQuery)
+	ts := httptest.NewServer(http.HandlerFunc(getHandlerMulti(t, requestQuery)))
+	t.Cleanup(ts.Close)
+	endpoint, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	c := &mockClient{url: endpoint}
+
+	channel := v1alpha2.ReleaseChannel{
+		Name:             "stable-4.0",
+		MinVersion:       "4.0.0-4",
+		MaxVersion:       "4.0.0-6",
+		PathStrategy:     v1alpha2.PathStrategyShortest,
+		StartingVersions: []string{"4.0.0-5", "4.0.0-4"},
+	}
+	opts := ReleaseOptions{}
+	dl, err := opts.getChannelDownloads(context.Background(), c, nil, channel, "test-arch")
+	require.NoError(t, err)
+	require.Equal(t, downloads{
+		"quay.io/openshift-release-dev/ocp-release:4.0.0-4": struct{}{},
+		"quay.io/openshift-release-dev/ocp-release:4.0.0-5": struct{}{},
+		"quay.io/openshift-release-dev/ocp-release:4.0.0-6": struct{}{},
+	}, dl)
+	require.Equal(t, []v1alpha2.UpgradePath{
+		{Channel: "stable-4.0", Versions: []string{"4.0.0-4", "4.0.0-5", "4.0.0-6"}},
+		{Channel: "stable-4.0", Versions: []string{"4.0.0-5", "4.0.0-6"}},
+	}, opts.paths)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-4", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-4"},
+		{Channel: "stable-4.0", Version: "4.0.0-5", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-5"},
+		{Channel: "stable-4.0", Version: "4.0.0-6", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-6"},
+	}, opts.releases)
+}
+
+func TestGetMinMaxDownloads(t *testing.T) {
+	requestQuery := make(chan string, 10)
+	defer close(requestQuery)
+	ts := httptest.NewServer(http.HandlerFunc(getHandlerMulti(t, requestQuery)))
+	t.Cleanup(ts.Close)
+	endpoint, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	c := &mockClient{url: endpoint}
+
+	prevChannel := v1alpha2.ReleaseChannel{
+		Name:       "stable-4.0",
+		MinVersion: "4.0.0-5",
+		MaxVersion: "4.0.0-5",
+	}
+	channel := prevChannel
+	channel.MaxVersion = "4.0.0-6"
+
+	opts := ReleaseOptions{}
+	dl, err := opts.getChannelDownloads(context.Background(), c, []v1alpha2.ReleaseChannel{prevChannel}, channel, "test-arch")
+	require.NoError(t, err)
+	require.Equal(t, downloads{
+		"quay.io/openshift-release-dev/ocp-release:4.0.0-5": struct{}{},
+		"quay.io/openshift-release-dev/ocp-release:4.0.0-6": struct{}{},
+	}, dl)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.0", Version: "4.0.0-5", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-5"},
+		{Channel: "stable-4.0", Version: "4.0.0-6", Image: "quay.io/openshift-release-dev/ocp-release:4.0.0-6"},
+	}, opts.releases)
+}
+
+func TestGetExplicitDownloads(t *testing.T) {
+	opts := ReleaseOptions{}
+	dl := opts.getExplicitDownloads([]v1alpha2.ReleaseImage{
+		{Image: v1alpha2.Image{Name: "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000"}},
+		{Image: v1alpha2.Image{Name: "quay.io/openshift-release-dev/ocp-release@sha256:0000000000000000000000000000000000000000000000000000000000000000"}},
+		{Image: v1alpha2.Image{Name: "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000"}},
+	})
+	require.Equal(t, downloads{
+		"registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000":                                          struct{}{},
+		"quay.io/openshift-release-dev/ocp-release@sha256:0000000000000000000000000000000000000000000000000000000000000000": struct{}{},
+	}, dl)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Image: "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000"},
+		{Image: "quay.io/openshift-release-dev/ocp-release@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+	}, opts.releases)
+}
+
+func TestDiffReleases(t *testing.T) {
+	last := []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.10", Version: "4.10.9", Image: "quay.io/openshift-release-dev/ocp-release:4.10.9"},
+		{Channel: "stable-4.10", Version: "4.10.10", Image: "quay.io/openshift-release-dev/ocp-release:4.10.10"},
+		{Channel: "stable-4.9", Version: "4.9.10", Image: "quay.io/openshift-release-dev/ocp-release:4.9.10"},
+	}
+	current := []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.10", Version: "4.10.11", Image: "quay.io/openshift-release-dev/ocp-release:4.10.11"},
+		{Channel: "stable-4.10", Version: "4.10.10", Image: "quay.io/openshift-release-dev/ocp-release:4.10.10"},
+		{Channel: "fast-4.9", Version: "4.9.10", Image: "quay.io/openshift-release-dev/ocp-release:4.9.10"},
+	}
+
+	added, removed := diffReleases(last, current)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "fast-4.9", Version: "4.9.10", Image: "quay.io/openshift-release-dev/ocp-release:4.9.10"},
+		{Channel: "stable-4.10", Version: "4.10.11", Image: "quay.io/openshift-release-dev/ocp-release:4.10.11"},
+	}, added)
+	require.Equal(t, []v1alpha2.ReleaseMetadata{
+		{Channel: "stable-4.10", Version: "4.10.9", Image: "quay.io/openshift-release-dev/ocp-release:4.10.9"},
+		{Channel: "stable-4.9", Version: "4.9.10", Image: "quay.io/openshift-release-dev/ocp-release:4.9.10"},
+	}, removed)
+}
+
+func TestRecordConditionalUpdates(t *testing.T) {
+	opts := ReleaseOptions{}
+	opts.recordConditionalUpdates([]cincinnati.Update{
+		{Version: semver.MustParse("4.9.10"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.10", Risks: []cincinnati.ConditionalUpdateRisk{{
+			Name:          "TestRisk",
+			URL:           "https://access.redhat.com/solutions/0000000",
+			MatchingRules: []cincinnati.ClusterCondition{{Type: "PromQL", PromQL: &cincinnati.PromQLQuery{PromQL: "up"}}},
+		}}},
+		{Version: semver.MustParse("4.9.9"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.9"},
+		{Version: semver.MustParse("4.9.2"), Image: "quay.io/openshift-release-dev/ocp-release:4.9.2", Risks: []cincinnati.ConditionalUpdateRisk{{
+			Name: "OtherRisk",
+			URL:  "https://access.redhat.com/solutions/0000001",
+		}}},
+	})
+
+	exp := []v1alpha2.ConditionalRelease{
+		{
+			Version: "4.9.2",
+			Image:   "quay.io/openshift-release-dev/ocp-release:4.9.2",
+			Risks:   []v1alpha2.UpdateRisk{{Name: "OtherRisk", URL: "https://access.redhat.com/solutions/0000001"}},
+		},
+		{
+			Version: "4.9.10",
+			Image:   "quay.io/openshift-release-dev/ocp-release:4.9.10",
+			Risks:   []v1alpha2.UpdateRisk{{Name: "TestRisk", URL: "https://access.redhat.com/solutions/0000000", PromQL: []string{"up"}}},
+		},
+	}
+	require.Equal(t, exp, opts.conditionalReleases())
+
+	var buf bytes.Buffer
+	require.NoError(t, writeConditionalReleases(&buf, exp))
+	require.Equal(t, `
+Releases planned on conditional updates:
+VERSION  RISK       URL
+4.9.2    OtherRisk  https://access.redhat.com/solutions/0000001
+4.9.10   TestRisk   https://access.redhat.com/solutions/0000000
+`, buf.String())
+}
+
 // Create a mock client
 type mockClient struct {
 	url *url.URL
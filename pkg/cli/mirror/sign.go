@@ -0,0 +1,295 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+const (
+	// cosignPasswordEnv is the environment variable
+	// holding the password of an encrypted signing key.
+	cosignPasswordEnv = "COSIGN_PASSWORD"
+	// cosignSignatureAnnotation is the layer annotation
+	// holding the signature of a simple signing payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// simpleSigningMediaType is the media type of signature payload layers.
+	simpleSigningMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// simpleSigningType identifies a payload as a container image signature.
+	simpleSigningType = "cosign container image signature"
+	// signingPublicKeyFile is the name of the public key
+	// written to the results directory.
+	signingPublicKeyFile = "cosign.pub"
+)
+
+// PEM block types of supported signing keys.
+const (
+	encryptedCosignKeyType   = "ENCRYPTED COSIGN PRIVATE KEY"
+	encryptedSigstoreKeyType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	ecPrivateKeyType         = "EC PRIVATE KEY"
+	pkcs8PrivateKeyType      = "PRIVATE KEY"
+)
+
+// encryptedKey is the JSON envelope of an encrypted cosign private key.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// simpleSigningPayload is the payload signed for each image.
+type simpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// loadSigningKey reads a cosign-compatible ECDSA private key from path. Encrypted
+// cosign keys are decrypted with the password in the COSIGN_PASSWORD environment variable.
+func loadSigningKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing key: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case encryptedCosignKeyType, encryptedSigstoreKeyType:
+		der, err := decryptKey(block.Bytes, []byte(os.Getenv(cosignPasswordEnv)))
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting signing key %s: %v", path, err)
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key %s: %v", path, err)
+		}
+	case ecPrivateKeyType:
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key %s: %v", path, err)
+		}
+	case pkcs8PrivateKeyType:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key %s: %v", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("signing key %s has unsupported PEM type %q", path, block.Type)
+	}
+
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ECDSA key", path)
+	}
+	return ecKey, nil
+}
+
+// decryptKey decrypts an encrypted cosign private key envelope.
+func decryptKey(data, password []byte) ([]byte, error) {
+	var ek encryptedKey
+	if err := json.Unmarshal(data, &ek); err != nil {
+		return nil, err
+	}
+	if ek.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported key derivation function %q", ek.KDF.Name)
+	}
+	if ek.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported cipher %q", ek.Cipher.Name)
+	}
+	if len(ek.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce length")
+	}
+	derived, err := scrypt.Key(password, ek.KDF.Salt, ek.KDF.Params.N, ek.KDF.Params.R, ek.KDF.Params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], derived)
+	copy(nonce[:], ek.Cipher.Nonce)
+	der, ok := secretbox.Open(nil, ek.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, fmt.Errorf("invalid password, check %s", cosignPasswordEnv)
+	}
+	return der, nil
+}
+
+// signResults signs the rebuilt catalog images in mapping, or every image in mapping
+// when --sign-all is set, and writes the public key of the signing key to dir.
+// Signatures are pushed as cosign signature images to the destination registry.
+func (o *MirrorOptions) signResults(ctx context.Context, mapping image.TypedImageMapping, dir string) error {
+	if o.signingKey == nil {
+		return nil
+	}
+	toSign := mapping
+	if !o.SignAll {
+		toSign = image.ByCategory(mapping, image.TypeOperatorCatalog)
+	}
+	if o.DryRun {
+		logrus.Infof("Dry run: would sign %d images", len(toSign))
+		return nil
+	}
+
+	var errs []error
+	for _, dst := range toSign {
+		if dst.Type != imagesource.DestinationRegistry || strings.HasPrefix(dst.Ref.Tag, "sha256-") {
+			continue
+		}
+		if dst.Ref.ID == "" {
+			logrus.Warnf("skipping signing of image %s: digest unknown", dst.Ref.Exact())
+			continue
+		}
+		ref, err := name.NewDigest(dst.Ref.AsRepository().Exact()+"@"+dst.Ref.ID, o.getNameOpts()...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := signImage(o.signingKey, ref, o.getRemoteOpts(ctx)...); err != nil {
+			errs = append(errs, fmt.Errorf("error signing image %s: %v", ref, err))
+			continue
+		}
+		logrus.Debugf("Signed image %s", ref)
+	}
+	if len(errs) != 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	return writePublicKey(dir, &o.signingKey.PublicKey)
+}
+
+// signImage signs ref with key and adds the signature to the
+// cosign signature image of ref, creating it if needed.
+func signImage(key *ecdsa.PrivateKey, ref name.Digest, opts ...remote.Option) error {
+	var p simpleSigningPayload
+	p.Critical.Identity.DockerReference = ref.Context().Name()
+	p.Critical.Image.DockerManifestDigest = ref.DigestStr()
+	p.Critical.Type = simpleSigningType
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		return err
+	}
+
+	sigTag := ref.Context().Tag(strings.Replace(ref.DigestStr(), ":", "-", 1) + ".sig")
+	base, err := remote.Image(sigTag, opts...)
+	switch {
+	case isNotFound(err):
+		base = mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON)
+	case err != nil:
+		return err
+	default:
+		signed, err := hasSignature(base, payload, &key.PublicKey)
+		if err != nil {
+			return err
+		}
+		if signed {
+			logrus.Debugf("Image %s is already signed", ref)
+			return nil
+		}
+	}
+
+	img, err := mutate.Append(base, mutate.Addendum{
+		Layer: static.NewLayer(payload, simpleSigningMediaType),
+		Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return remote.Write(sigTag, img, opts...)
+}
+
+// hasSignature reports whether img contains a signature
+// of payload that is valid for pub.
+func hasSignature(img v1.Image, payload []byte, pub *ecdsa.PublicKey) (bool, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(payload)
+	for _, desc := range manifest.Layers {
+		if desc.Digest.Hex != fmt.Sprintf("%x", hash) {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(desc.Annotations[cosignSignatureAnnotation])
+		if err != nil {
+			continue
+		}
+		if ecdsa.VerifyASN1(pub, hash[:], sig) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// writePublicKey writes pub to dir in the PEM format used by cosign.
+func writePublicKey(dir string, pub *ecdsa.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: "PUBLIC KEY", Bytes: der}); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, signingPublicKeyFile)
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	logrus.Infof("Wrote signing public key to %s", path)
+	return nil
+}
@@ -0,0 +1,217 @@
+package mirror
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/openshift/oc-mirror/pkg/image"
+)
+
+// encryptTestKey encrypts der the same way as cosign generate-key-pair.
+func encryptTestKey(t *testing.T, der, password []byte) []byte {
+	var ek encryptedKey
+	ek.KDF.Name = "scrypt"
+	ek.KDF.Params.N, ek.KDF.Params.R, ek.KDF.Params.P = 1024, 8, 1
+	ek.KDF.Salt = make([]byte, 32)
+	_, err := rand.Read(ek.KDF.Salt)
+	require.NoError(t, err)
+	ek.Cipher.Name = "nacl/secretbox"
+	ek.Cipher.Nonce = make([]byte, 24)
+	_, err = rand.Read(ek.Cipher.Nonce)
+	require.NoError(t, err)
+
+	derived, err := scrypt.Key(password, ek.KDF.Salt, ek.KDF.Params.N, ek.KDF.Params.R, ek.KDF.Params.P, 32)
+	require.NoError(t, err)
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], derived)
+	copy(nonce[:], ek.Cipher.Nonce)
+	ek.Ciphertext = secretbox.Seal(nil, der, &nonce, &key)
+
+	data, err := json.Marshal(ek)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedSigstoreKeyType, Bytes: data})
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+
+	type spec struct {
+		name     string
+		data     []byte
+		password string
+		err      string
+	}
+
+	cases := []spec{
+		{
+			name:     "Valid/Encrypted",
+			data:     encryptTestKey(t, pkcs8, []byte("secret")),
+			password: "secret",
+		},
+		{
+			name: "Valid/ECPrivateKey",
+			data: pem.EncodeToMemory(&pem.Block{Type: ecPrivateKeyType, Bytes: sec1}),
+		},
+		{
+			name: "Valid/PKCS8",
+			data: pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyType, Bytes: pkcs8}),
+		},
+		{
+			name:     "Invalid/Password",
+			data:     encryptTestKey(t, pkcs8, []byte("secret")),
+			password: "wrong",
+			err:      "error decrypting signing key %s: invalid password, check COSIGN_PASSWORD",
+		},
+		{
+			name: "Invalid/RSAKey",
+			data: pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyType, Bytes: rsaPKCS8}),
+			err:  "signing key %s is not an ECDSA key",
+		},
+		{
+			name: "Invalid/NotPEM",
+			data: []byte("not a key"),
+			err:  "signing key %s is not PEM encoded",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cosign.key")
+			require.NoError(t, ioutil.WriteFile(path, c.data, 0600))
+			prev, set := os.LookupEnv(cosignPasswordEnv)
+			require.NoError(t, os.Setenv(cosignPasswordEnv, c.password))
+			defer func() {
+				if set {
+					os.Setenv(cosignPasswordEnv, prev)
+				} else {
+					os.Unsetenv(cosignPasswordEnv)
+				}
+			}()
+
+			key, err := loadSigningKey(path)
+			if c.err != "" {
+				require.EqualError(t, err, fmt.Sprintf(c.err, path))
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ecKey.Equal(key))
+		})
+	}
+}
+
+func TestSignResults(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pushImage := func(repo, contents string, typ image.ImageType) (image.TypedImage, string) {
+		img, err := crane.Image(map[string][]byte{"foo": []byte(contents)})
+		require.NoError(t, err)
+		dgst, err := img.Digest()
+		require.NoError(t, err)
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s@%s", u.Host, repo, dgst))
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, img))
+		dst, err := image.ParseTypedImage(ref.String(), typ)
+		require.NoError(t, err)
+		return dst, dgst.String()
+	}
+	ctlg, ctlgDigest := pushImage("test/catalog", "catalog", image.TypeOperatorCatalog)
+	generic, genericDigest := pushImage("test/generic", "generic", image.TypeGeneric)
+	mapping := image.TypedImageMapping{
+		{Category: image.TypeOperatorCatalog}: ctlg,
+		{Category: image.TypeGeneric}:         generic,
+	}
+
+	sigLayers := func(repo, dgst string) int {
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s:%s.sig", u.Host, repo, strings.Replace(dgst, ":", "-", 1)))
+		require.NoError(t, err)
+		img, err := remote.Image(ref)
+		if isNotFound(err) {
+			return 0
+		}
+		require.NoError(t, err)
+		manifest, err := img.Manifest()
+		require.NoError(t, err)
+		layers, err := img.Layers()
+		require.NoError(t, err)
+		for i, desc := range manifest.Layers {
+			require.Equal(t, simpleSigningMediaType, desc.MediaType)
+			rc, err := layers[i].Uncompressed()
+			require.NoError(t, err)
+			payload, err := ioutil.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+
+			var p simpleSigningPayload
+			require.NoError(t, json.Unmarshal(payload, &p))
+			require.Equal(t, fmt.Sprintf("%s/%s", u.Host, repo), p.Critical.Identity.DockerReference)
+			require.Equal(t, dgst, p.Critical.Image.DockerManifestDigest)
+			require.Equal(t, simpleSigningType, p.Critical.Type)
+
+			sig, err := base64.StdEncoding.DecodeString(desc.Annotations[cosignSignatureAnnotation])
+			require.NoError(t, err)
+			hash := sha256.Sum256(payload)
+			require.True(t, ecdsa.VerifyASN1(&key.PublicKey, hash[:], sig))
+		}
+		return len(manifest.Layers)
+	}
+
+	dir := t.TempDir()
+	opts := &MirrorOptions{DestPlainHTTP: true, signingKey: key}
+	require.NoError(t, opts.signResults(context.Background(), mapping, dir))
+	require.Equal(t, 1, sigLayers("test/catalog", ctlgDigest))
+	require.Equal(t, 0, sigLayers("test/generic", genericDigest))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, signingPublicKeyFile))
+	require.NoError(t, err)
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(pub))
+
+	// Signing again does not duplicate existing signatures.
+	opts.SignAll = true
+	require.NoError(t, opts.signResults(context.Background(), mapping, dir))
+	require.Equal(t, 1, sigLayers("test/catalog", ctlgDigest))
+	require.Equal(t, 1, sigLayers("test/generic", genericDigest))
+}
@@ -0,0 +1,336 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/verify"
+	"github.com/openshift/library-go/pkg/verify/store"
+	"github.com/openshift/library-go/pkg/verify/store/sigstore"
+	"github.com/openshift/library-go/pkg/verify/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/oc-mirror/pkg/config"
+)
+
+const (
+	// defaultReleaseSignatureStore is the location of the
+	// signatures for OpenShift release payloads.
+	defaultReleaseSignatureStore = "https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release"
+	// signatureFilePrefix is the prefix of each signature
+	// file within a digest directory of a signature store.
+	signatureFilePrefix = "signature-"
+	// maxReleaseSignatures bounds the number of
+	// signatures read for a single release digest.
+	maxReleaseSignatures = 10
+)
+
+// dirSignatureStore reads release signatures from a local directory laid out
+// like the release signature store, <dir>/<algo>=<hash>/signature-<n>.
+type dirSignatureStore struct {
+	dir string
+}
+
+// Signatures reads the signatures for digest until fn is done or no signature is found.
+func (s *dirSignatureStore) Signatures(ctx context.Context, _ string, digest string, fn store.Callback) error {
+	digestDir, err := util.DigestToKeyPrefix(digest, "=")
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= maxReleaseSignatures; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, digestDir, signatureFilePrefix+strconv.Itoa(i)))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		done, err := fn(ctx, data, err)
+		if done || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *dirSignatureStore) String() string {
+	return fmt.Sprintf("file://%s", s.dir)
+}
+
+// releaseSignatureStore returns the store release signatures are collected
+// from. A local signature directory takes precedence over a remote store, and
+// the default store is used when only a release keyring is configured.
+func (o *ReleaseOptions) releaseSignatureStore() (store.Store, error) {
+	if len(o.ReleaseSignatureDir) > 0 {
+		return &dirSignatureStore{dir: o.ReleaseSignatureDir}, nil
+	}
+	storeURL := o.ReleaseSignatureStore
+	if len(storeURL) == 0 {
+		storeURL = defaultReleaseSignatureStore
+	}
+	uri, err := url.Parse(storeURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release signature store %q: %v", storeURL, err)
+	}
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, fmt.Errorf("invalid release signature store %q: scheme must be http or https", storeURL)
+	}
+	return &sigstore.Store{
+		URI: uri,
+		HTTPClient: func() (*http.Client, error) {
+			return &http.Client{Transport: newTransport(o.insecure)}, nil
+		},
+	}, nil
+}
+
+// getReleaseSignatures returns all signatures for digest in s.
+// The first retrieval error is returned if no signature was found.
+func getReleaseSignatures(ctx context.Context, s store.Store, digest string) ([][]byte, error) {
+	var signatures [][]byte
+	var firstErr error
+	err := s.Signatures(ctx, "", digest, func(ctx context.Context, signature []byte, errIn error) (bool, error) {
+		if errIn != nil {
+			logrus.Debugf("error retrieving signature for %s: %v", digest, errIn)
+			if firstErr == nil {
+				firstErr = errIn
+			}
+			return false, nil
+		}
+		signatures = append(signatures, signature)
+		return len(signatures) >= maxReleaseSignatures, nil
+	})
+	if err == nil && len(signatures) == 0 {
+		err = firstErr
+	}
+	return signatures, err
+}
+
+// writeReleaseSignatures writes signatures for digest to dir
+// using the same layout as the release signature store.
+func writeReleaseSignatures(dir, digest string, signatures [][]byte) error {
+	digestDir, err := util.DigestToKeyPrefix(digest, "=")
+	if err != nil {
+		return err
+	}
+	sigDir := filepath.Join(dir, digestDir)
+	if err := os.MkdirAll(sigDir, os.ModePerm); err != nil {
+		return err
+	}
+	for i, sig := range signatures {
+		if err := ioutil.WriteFile(filepath.Join(sigDir, signatureFilePrefix+strconv.Itoa(i+1)), sig, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readReleaseSignatures reads all signatures in dir keyed by release digest.
+func readReleaseSignatures(dir string) (map[string][][]byte, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &dirSignatureStore{dir: dir}
+	signatures := map[string][][]byte{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		digest := strings.Replace(entry.Name(), "=", ":", 1)
+		sigs, err := getReleaseSignatures(context.Background(), s, digest)
+		if err != nil {
+			return nil, fmt.Errorf("error reading release signatures in %s: %v", entry.Name(), err)
+		}
+		if len(sigs) != 0 {
+			signatures[digest] = sigs
+		}
+	}
+	return signatures, nil
+}
+
+// collectReleaseSignatures fetches the signatures for each release image
+// and writes them to the workspace to be packed into the imageset.
+// Signatures are only collected when a signature store, signature directory,
+// or release keyring is configured. Missing signatures and retrieval errors
+// are logged and only fail the operation when a release keyring is configured.
+func (o *ReleaseOptions) collectReleaseSignatures(ctx context.Context, releases []string) error {
+	if len(o.ReleaseSignatureStore) == 0 && len(o.ReleaseSignatureDir) == 0 && len(o.ReleaseKeyring) == 0 {
+		logrus.Debug("No release signature store configured, skipping signature collection")
+		return nil
+	}
+	if o.DryRun {
+		logrus.Infof("Dry run: would collect signatures for %d releases", len(releases))
+		return nil
+	}
+	s, err := o.releaseSignatureStore()
+	if err != nil {
+		return err
+	}
+	sigDir := filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir)
+	for _, img := range releases {
+		digest, err := releaseDigest(img)
+		if err != nil {
+			return err
+		}
+		signatures, err := getReleaseSignatures(ctx, s, digest)
+		switch {
+		case err != nil && len(o.ReleaseKeyring) != 0:
+			return fmt.Errorf("error retrieving signatures for release %s from %s: %v", img, s, err)
+		case err != nil:
+			logrus.Warnf("error retrieving signatures for release %s from %s: %v", img, s, err)
+			continue
+		case len(signatures) == 0:
+			logrus.Warnf("no signatures found for release %s in %s", img, s)
+			continue
+		}
+		logrus.Debugf("Found %d signature(s) for release %s", len(signatures), img)
+		if err := writeReleaseSignatures(sigDir, digest, signatures); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyReleaseSignatures verifies that every release image is signed by the
+// configured keyring using the signatures collected in the workspace. Releases
+// without a valid signature, including releases not referenced by digest, fail
+// verification unless verification is skipped.
+func (o *ReleaseOptions) verifyReleaseSignatures(ctx context.Context, releases []string) error {
+	if len(o.ReleaseKeyring) == 0 {
+		return nil
+	}
+	if o.SkipVerification {
+		logrus.Warn("skipping release signature verification")
+		return nil
+	}
+	if o.DryRun {
+		logrus.Infof("Dry run: would verify signatures for %d releases", len(releases))
+		return nil
+	}
+	keyring, err := loadKeyring(o.ReleaseKeyring)
+	if err != nil {
+		return err
+	}
+	sigDir := filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir)
+	verifier := verify.NewReleaseVerifier(
+		map[string]openpgp.EntityList{o.ReleaseKeyring: keyring},
+		&dirSignatureStore{dir: sigDir},
+	)
+
+	var errs []error
+	for _, img := range releases {
+		// Signatures are only published for releases referenced by digest.
+		digest, err := releaseDigest(img)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("release %s failed signature verification: %v", img, err))
+			continue
+		}
+		if err := verifier.Verify(ctx, digest); err != nil {
+			errs = append(errs, fmt.Errorf("release %s failed signature verification: %v", img, err))
+			continue
+		}
+		logrus.Debugf("Verified signature for release %s", img)
+	}
+	if len(errs) != 0 {
+		errs = append(errs, errors.New("use --skip-verification to bypass release signature verification"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// loadKeyring reads an armored or binary GPG keyring from path.
+func loadKeyring(path string) (openpgp.EntityList, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading release keyring: %v", err)
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release keyring %s: %v", path, err)
+	}
+	return keyring, nil
+}
+
+// releaseDigest returns the digest of a release image pullspec.
+func releaseDigest(img string) (string, error) {
+	i := strings.LastIndex(img, "@")
+	if i == -1 {
+		return "", fmt.Errorf("release image %s is not referenced by digest", img)
+	}
+	return img[i+1:], nil
+}
+
+// stageReleaseSignatures moves the release signatures unpacked from an
+// imageset into dir to the workspace.
+func (o *MirrorOptions) stageReleaseSignatures(dir string) error {
+	src := filepath.Join(dir, config.ReleaseSignaturesDir)
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		logrus.Debug("No release signatures found in imageset")
+		return nil
+	}
+	dst := filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir)
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// writeSignatureConfigMaps writes a release signature ConfigMap for each
+// release signed in the signature directory of the workspace to each of dirs.
+// The cluster-version operator reads these ConfigMaps to verify releases
+// when the signature store is not reachable.
+func (o *MirrorOptions) writeSignatureConfigMaps(dirs ...string) error {
+	sigDir := filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir)
+	signatures, err := readReleaseSignatures(sigDir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logrus.Debug("No release signatures found, skipping signature ConfigMaps")
+		return nil
+	case err != nil:
+		return err
+	}
+
+	digests := make([]string, 0, len(signatures))
+	for digest := range signatures {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+
+	for _, digest := range digests {
+		cm, err := verify.GetSignaturesAsConfigmap(digest, signatures[digest])
+		if err != nil {
+			return err
+		}
+		data, err := util.ConfigMapAsBytes(cm)
+		if err != nil {
+			return err
+		}
+		for _, dir := range dirs {
+			path := filepath.Join(dir, fmt.Sprintf("signature-%s.yaml", cm.Name))
+			if err := ioutil.WriteFile(path, data, 0640); err != nil {
+				return err
+			}
+		}
+	}
+	if len(digests) != 0 {
+		logrus.Infof("Wrote release signature ConfigMaps to %s", strings.Join(dirs, ", "))
+	}
+	return nil
+}
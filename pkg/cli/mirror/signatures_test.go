@@ -0,0 +1,265 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/verify/util"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/pkg/cli"
+	"github.com/openshift/oc-mirror/pkg/config"
+)
+
+const testReleaseDigest = "sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001"
+
+func TestCollectReleaseSignatures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sha256=d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001/signature-1":
+			_, _ = w.Write([]byte("sig1"))
+		case "/sha256=d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001/signature-2":
+			_, _ = w.Write([]byte("sig2"))
+		case "/sha256=1111111111111111111111111111111111111111111111111111111111111111/signature-1":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	localDir := t.TempDir()
+	require.NoError(t, writeReleaseSignatures(localDir, testReleaseDigest, [][]byte{[]byte("local1")}))
+
+	errRelease := "quay.io/openshift-release-dev/ocp-release@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	errSigURL, err := url.Parse(server.URL + "/sha256=1111111111111111111111111111111111111111111111111111111111111111/signature-1")
+	require.NoError(t, err)
+	errSigURL.Path = strings.TrimPrefix(errSigURL.Path, "/")
+
+	type spec struct {
+		name     string
+		store    string
+		dir      string
+		keyring  string
+		dryRun   bool
+		releases []string
+		expected map[string][][]byte
+		err      string
+	}
+
+	cases := []spec{
+		{
+			name:     "Valid/Store",
+			store:    server.URL,
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			expected: map[string][][]byte{testReleaseDigest: {[]byte("sig1"), []byte("sig2")}},
+		},
+		{
+			name:     "Valid/Directory",
+			store:    server.URL,
+			dir:      localDir,
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			expected: map[string][][]byte{testReleaseDigest: {[]byte("local1")}},
+		},
+		{
+			name:     "Valid/Unsigned",
+			store:    server.URL,
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+			expected: map[string][][]byte{},
+		},
+		{
+			name:     "Valid/NotConfigured",
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			expected: map[string][][]byte{},
+		},
+		{
+			name:     "Valid/DryRun",
+			store:    server.URL,
+			dryRun:   true,
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			expected: map[string][][]byte{},
+		},
+		{
+			name:     "Valid/StoreError",
+			store:    server.URL,
+			releases: []string{errRelease, "quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			expected: map[string][][]byte{testReleaseDigest: {[]byte("sig1"), []byte("sig2")}},
+		},
+		{
+			name:     "Invalid/StoreErrorWithKeyring",
+			store:    server.URL,
+			keyring:  "keyring.gpg",
+			releases: []string{errRelease},
+			// The signature store formats the signature URL as a url.URL value.
+			err: fmt.Sprintf("error retrieving signatures for release %s from containers/image signature store under %s: "+
+				"unable to retrieve signature from %v: 500", errRelease, server.URL, *errSigURL),
+		},
+		{
+			name:     "Invalid/StoreScheme",
+			store:    "file:///signatures",
+			releases: []string{"quay.io/openshift-release-dev/ocp-release@" + testReleaseDigest},
+			err:      `invalid release signature store "file:///signatures": scheme must be http or https`,
+		},
+		{
+			name:     "Invalid/TagReference",
+			store:    server.URL,
+			releases: []string{"quay.io/openshift-release-dev/ocp-release:4.9.0"},
+			err:      "release image quay.io/openshift-release-dev/ocp-release:4.9.0 is not referenced by digest",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &ReleaseOptions{
+				MirrorOptions: &MirrorOptions{
+					RootOptions:           &cli.RootOptions{Dir: t.TempDir()},
+					ReleaseSignatureStore: c.store,
+					ReleaseSignatureDir:   c.dir,
+					ReleaseKeyring:        c.keyring,
+					DryRun:                c.dryRun,
+				},
+			}
+			err := o.collectReleaseSignatures(context.Background(), c.releases)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+
+			if len(c.expected) == 0 {
+				require.NoDirExists(t, filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir))
+				return
+			}
+			signatures, err := readReleaseSignatures(filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir))
+			require.NoError(t, err)
+			require.Equal(t, c.expected, signatures)
+		})
+	}
+}
+
+func TestWriteSignatureConfigMaps(t *testing.T) {
+	o := &MirrorOptions{RootOptions: &cli.RootOptions{Dir: t.TempDir()}}
+	sigDir := filepath.Join(o.Dir, config.SourceDir, config.ReleaseSignaturesDir)
+	require.NoError(t, writeReleaseSignatures(sigDir, testReleaseDigest, [][]byte{[]byte("sig1"), []byte("sig2")}))
+
+	resultsDir := t.TempDir()
+	require.NoError(t, o.writeSignatureConfigMaps(resultsDir))
+
+	data, err := ioutil.ReadFile(filepath.Join(resultsDir,
+		"signature-sha256-d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001.yaml"))
+	require.NoError(t, err)
+	cm, err := util.ReadConfigMap(data)
+	require.NoError(t, err)
+	require.Equal(t, "openshift-config-managed", cm.Namespace)
+	require.Equal(t, "sha256-d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001", cm.Name)
+	require.Contains(t, cm.Labels, "release.openshift.io/verification-signatures")
+	require.Equal(t, map[string][]byte{
+		"sha256-d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001-1": []byte("sig1"),
+		"sha256-d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001-2": []byte("sig2"),
+	}, cm.BinaryData)
+
+	// No signatures in the workspace is not an error.
+	empty := &MirrorOptions{RootOptions: &cli.RootOptions{Dir: t.TempDir()}}
+	require.NoError(t, empty.writeSignatureConfigMaps(resultsDir))
+}
+
+func TestVerifyReleaseSignatures(t *testing.T) {
+	const (
+		redhatSigned = "sha256:e3f12513a4b22a2d7c0e7c9207f52128113758d9d68c7d06b11a0ac7672966f7"
+		unsigned     = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+		releaseRepo  = "quay.io/openshift-release-dev/ocp-release@"
+	)
+	testdata := filepath.Join("testdata", "release-signatures")
+
+	type spec struct {
+		name     string
+		keyring  string
+		skip     bool
+		releases []string
+		err      string
+	}
+
+	cases := []spec{
+		{
+			name:     "Valid/Signed",
+			keyring:  filepath.Join(testdata, "keyrings", "redhat.txt"),
+			releases: []string{releaseRepo + redhatSigned},
+		},
+		{
+			name:     "Valid/NoKeyring",
+			releases: []string{releaseRepo + unsigned},
+		},
+		{
+			name:     "Valid/SkipVerification",
+			keyring:  filepath.Join(testdata, "keyrings", "redhat.txt"),
+			skip:     true,
+			releases: []string{releaseRepo + unsigned},
+		},
+		{
+			name:     "Invalid/Unsigned",
+			keyring:  filepath.Join(testdata, "keyrings", "redhat.txt"),
+			releases: []string{releaseRepo + redhatSigned, releaseRepo + unsigned},
+			err: "[release " + releaseRepo + unsigned + " failed signature verification: " +
+				"unable to locate a valid signature for one or more sources, " +
+				"use --skip-verification to bypass release signature verification]",
+		},
+		{
+			name:     "Invalid/UntrustedKey",
+			keyring:  filepath.Join(testdata, "keyrings", "simple.txt"),
+			releases: []string{releaseRepo + redhatSigned},
+			err: "[release " + releaseRepo + redhatSigned + " failed signature verification: " +
+				"unable to locate a valid signature for one or more sources, " +
+				"use --skip-verification to bypass release signature verification]",
+		},
+		{
+			name:     "Invalid/TagReference",
+			keyring:  filepath.Join(testdata, "keyrings", "redhat.txt"),
+			releases: []string{releaseRepo + redhatSigned, "quay.io/openshift-release-dev/ocp-release:4.9.0"},
+			err: "[release quay.io/openshift-release-dev/ocp-release:4.9.0 failed signature verification: " +
+				"release image quay.io/openshift-release-dev/ocp-release:4.9.0 is not referenced by digest, " +
+				"use --skip-verification to bypass release signature verification]",
+		},
+		{
+			name:     "Invalid/MissingKeyring",
+			keyring:  filepath.Join(testdata, "keyrings", "missing.txt"),
+			releases: []string{releaseRepo + redhatSigned},
+			err: "error reading release keyring: open " + filepath.Join(testdata, "keyrings", "missing.txt") +
+				": no such file or directory",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &ReleaseOptions{
+				MirrorOptions: &MirrorOptions{
+					RootOptions:         &cli.RootOptions{Dir: t.TempDir()},
+					ReleaseSignatureDir: filepath.Join(testdata, "signatures"),
+					ReleaseKeyring:      c.keyring,
+					SkipVerification:    c.skip,
+				},
+			}
+			ctx := context.Background()
+			// Signatures are only collected for releases referenced by digest.
+			var signed []string
+			for _, img := range c.releases {
+				if _, err := releaseDigest(img); err == nil {
+					signed = append(signed, img)
+				}
+			}
+			require.NoError(t, o.collectReleaseSignatures(ctx, signed))
+			err := o.verifyReleaseSignatures(ctx, c.releases)
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
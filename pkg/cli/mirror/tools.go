@@ -0,0 +1,99 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/image/reference"
+	"github.com/openshift/oc/pkg/cli/admin/release"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/oc-mirror/pkg/config"
+)
+
+// supportedToolsOS are the operating systems release tools can be extracted for.
+// An empty value selects the operating system oc-mirror is running on.
+var supportedToolsOS = map[string]struct{}{"": {}, "*": {}, "linux": {}, "mac": {}, "windows": {}}
+
+// extractReleaseTools extracts the oc and openshift-install archives of each
+// release into the tools directory of the workspace, which is packed into the
+// imageset. Tools extracted by previous runs are removed so only the tools of
+// the releases planned by this run are packed.
+func (o *ReleaseOptions) extractReleaseTools(releases []string) error {
+	toolsDir := filepath.Join(o.Dir, config.SourceDir, config.ToolsDir)
+	if err := os.RemoveAll(toolsDir); err != nil {
+		return err
+	}
+
+	regctx, err := config.CreateDefaultContext(o.insecure)
+	if err != nil {
+		return fmt.Errorf("error creating registry context: %v", err)
+	}
+	for _, img := range releases {
+		name, err := releaseToolsDir(img)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(toolsDir, name)
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+
+		opts := release.NewExtractOptions(o.IOStreams, false)
+		opts.From = img
+		opts.Tools = true
+		opts.CommandOperatingSystem = o.ToolsOS
+		opts.Directory = dir
+		opts.SecurityOptions.Insecure = o.insecure
+		opts.SecurityOptions.SkipVerification = o.SkipVerification
+		opts.SecurityOptions.CachedContext = regctx
+
+		logrus.Infof("Extracting release tools from %s to %s", img, dir)
+		if err := opts.Run(); err != nil {
+			return fmt.Errorf("error extracting tools from release %s: %v", img, err)
+		}
+	}
+	return nil
+}
+
+// releaseToolsDir returns the path of the directory the tools of a release are
+// extracted to, relative to the tools directory. Releases referenced by digest are
+// extracted to a directory named by digest. Releases referenced by tag are extracted
+// to <registry>/<namespace>/<name>/<tag> so releases with the same tag in different
+// repositories are not extracted to the same directory.
+func releaseToolsDir(img string) (string, error) {
+	ref, err := reference.Parse(img)
+	if err != nil {
+		return "", fmt.Errorf("error parsing release image %s: %v", img, err)
+	}
+	if len(ref.ID) != 0 {
+		return strings.Replace(ref.ID, ":", "-", 1), nil
+	}
+	tag := ref.Tag
+	if len(tag) == 0 {
+		tag = "latest"
+	}
+	return filepath.Join(ref.Registry, ref.Namespace, ref.Name, tag), nil
+}
+
+// moveReleaseTools moves the release tools in src to dst.
+func moveReleaseTools(src, dst string) error {
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		logrus.Debug("No release tools found, skipping")
+		return nil
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	logrus.Infof("Wrote release tools to %s", dst)
+	return nil
+}
@@ -0,0 +1,82 @@
+package mirror
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseToolsDir(t *testing.T) {
+	type spec struct {
+		name   string
+		img    string
+		exp    string
+		expErr string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/Digest",
+			img:  "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+			exp:  "sha256-d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001",
+		},
+		{
+			name: "Valid/Tag",
+			img:  "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000",
+			exp:  filepath.Join("registry.ci.openshift.org", "ocp", "release", "4.11.0-0.nightly-2022-06-01-000000"),
+		},
+		{
+			// The same tag in another repository is extracted to a different directory.
+			name: "Valid/TagOtherRepository",
+			img:  "quay.io/openshift-release-dev/ocp-release:4.11.0-0.nightly-2022-06-01-000000",
+			exp:  filepath.Join("quay.io", "openshift-release-dev", "ocp-release", "4.11.0-0.nightly-2022-06-01-000000"),
+		},
+		{
+			name: "Valid/NoTag",
+			img:  "registry.ci.openshift.org/ocp/release",
+			exp:  filepath.Join("registry.ci.openshift.org", "ocp", "release", "latest"),
+		},
+		{
+			name:   "Invalid/Reference",
+			img:    "quay.io/openshift-release-dev/OCP-release:4.11",
+			expErr: "error parsing release image quay.io/openshift-release-dev/OCP-release:4.11: repository name must be lowercase",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := releaseToolsDir(c.img)
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.exp, dir)
+		})
+	}
+}
+
+func TestMoveReleaseTools(t *testing.T) {
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src", "tools")
+	dst := filepath.Join(tmpdir, "results", "tools")
+
+	// No tools to move
+	require.NoError(t, moveReleaseTools(src, dst))
+	require.NoDirExists(t, dst)
+
+	archive := filepath.Join("4.11.0", "openshift-client-linux-4.11.0.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "4.11.0"), 0750))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, archive), []byte("oc"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dst, "stale"), 0750))
+
+	require.NoError(t, moveReleaseTools(src, dst))
+	require.NoDirExists(t, src)
+	require.NoDirExists(t, filepath.Join(dst, "stale"))
+	data, err := ioutil.ReadFile(filepath.Join(dst, archive))
+	require.NoError(t, err)
+	require.Equal(t, "oc", string(data))
+}
@@ -4,12 +4,17 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"golang.org/x/crypto/ssh/terminal"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/oc-mirror/pkg/cincinnati"
+	"github.com/openshift/oc-mirror/pkg/config"
 )
 
 type RootOptions struct {
@@ -17,6 +22,10 @@ type RootOptions struct {
 
 	Dir      string
 	LogLevel string
+	// GraphCacheTTL is how long fetched update graphs are reused.
+	GraphCacheTTL time.Duration
+	// PersistGraphCache persists fetched update graphs to the workspace.
+	PersistGraphCache bool
 
 	logfileCleanup func()
 }
@@ -24,6 +33,9 @@ type RootOptions struct {
 func (o *RootOptions) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&o.Dir, "dir", "d", "oc-mirror-workspace", "Assets directory")
 	fs.StringVar(&o.LogLevel, "log-level", "info", "Log level (e.g. \"debug | info | warn | error\")")
+	fs.DurationVar(&o.GraphCacheTTL, "graph-cache-ttl", cincinnati.DefaultGraphCacheTTL, "How long fetched update graphs are reused (0 disables the cache)")
+	fs.BoolVar(&o.PersistGraphCache, "persist-graph-cache", false, "Persist fetched update graphs to the workspace "+
+		"to reuse them across invocations")
 	if err := fs.MarkHidden("dir"); err != nil {
 		logrus.Panic(err.Error())
 	}
@@ -61,6 +73,16 @@ func (o *RootOptions) LogfilePreRun(cmd *cobra.Command, _ []string) {
 	}
 }
 
+// GraphCachePreRun configures the update graph cache
+// shared by all commands that query Cincinnati.
+func (o *RootOptions) GraphCachePreRun(*cobra.Command, []string) {
+	var dir string
+	if o.PersistGraphCache {
+		dir = filepath.Join(o.Dir, config.GraphCacheDir)
+	}
+	cincinnati.ConfigureGraphCache(o.GraphCacheTTL, dir)
+}
+
 func (o *RootOptions) LogfilePostRun(*cobra.Command, []string) {
 	if o.logfileCleanup != nil {
 		o.logfileCleanup()
@@ -12,12 +12,26 @@ const (
 	V2Dir            = "v2"
 	BlobDir          = "blobs"
 	MetadataFile     = ".metadata.json"
-	AssociationsFile = "image-associations.gob"
+	AssociationsFile = "image-associations.json"
+	// LegacyAssociationsFile is the name of the gob-encoded
+	// image associations file written by older versions.
+	LegacyAssociationsFile = "image-associations.gob"
+	// ReleaseSignaturesDir contains the signatures
+	// of mirrored release payloads.
+	ReleaseSignaturesDir = "release-signatures"
+	// ToolsDir contains the oc and openshift-install
+	// archives extracted from mirrored releases.
+	ToolsDir = "tools"
+	// GraphCacheDir contains the persisted
+	// Cincinnati update graph cache.
+	GraphCacheDir = "graph-cache"
 )
 
 var (
 	MetadataBasePath = filepath.Join(PublishDir, MetadataFile)
 
-	// AssociationsBasePath stores image association data in opaque binary format.
+	// AssociationsBasePath stores image association data in versioned JSON format.
 	AssociationsBasePath = filepath.Join(InternalDir, AssociationsFile)
+	// LegacyAssociationsBasePath stores image association data in opaque binary format.
+	LegacyAssociationsBasePath = filepath.Join(InternalDir, LegacyAssociationsFile)
 )
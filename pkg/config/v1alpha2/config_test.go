@@ -146,6 +146,13 @@ mirror:
   - catalog: registry.com/ns/bar:v1.2
     headsOnly: true
   - catalog: registry.com/ns/baz:v1.2
+  ocp:
+    channels:
+    - name: stable-4.9
+    - name: stable-4.10
+      pathStrategy: shortest
+    - name: stable-4.11
+      keepLatest: 2
 `
 
 	cfg, err := LoadConfig([]byte(headsOnlyCfg))
@@ -154,4 +161,78 @@ mirror:
 	require.Equal(t, cfg.Mirror.Operators[0].IsHeadsOnly(), false)
 	require.Equal(t, cfg.Mirror.Operators[1].IsHeadsOnly(), true)
 	require.Equal(t, cfg.Mirror.Operators[2].IsHeadsOnly(), true)
+	require.Len(t, cfg.Mirror.OCP.Channels, 3)
+	require.Equal(t, cfg.Mirror.OCP.Channels[0].IsHeadsOnly(), true)
+	require.Equal(t, cfg.Mirror.OCP.Channels[1].IsHeadsOnly(), false)
+	require.Equal(t, cfg.Mirror.OCP.Channels[2].IsHeadsOnly(), false)
+}
+
+func TestVersionRange(t *testing.T) {
+	type spec struct {
+		name     string
+		channel  ReleaseChannel
+		matches  []string
+		excludes []string
+		expErr   string
+	}
+
+	cases := []spec{
+		{
+			name:     "Valid/CommaSeparated",
+			channel:  ReleaseChannel{VersionConstraint: ">=4.10.20 <4.11, !=4.10.33"},
+			matches:  []string{"4.10.20", "4.10.34"},
+			excludes: []string{"4.9.50", "4.10.19", "4.10.33", "4.11.0"},
+		},
+		{
+			name:     "Valid/MinorVersion",
+			channel:  ReleaseChannel{VersionConstraint: "4.10"},
+			matches:  []string{"4.10.0", "4.10.99"},
+			excludes: []string{"4.9.99", "4.11.0"},
+		},
+		{
+			name:     "Valid/Alternatives",
+			channel:  ReleaseChannel{VersionConstraint: "<4.9.2 || >=4.10"},
+			matches:  []string{"4.9.1", "4.10.0", "4.11.3"},
+			excludes: []string{"4.9.2", "4.9.10"},
+		},
+		{
+			name: "Valid/ExcludeVersions",
+			channel: ReleaseChannel{
+				VersionConstraint: ">=4.10.0 <=4.10.3",
+				ExcludeVersions:   []string{"4.10.1", "4.10.2"},
+			},
+			matches:  []string{"4.10.0", "4.10.3"},
+			excludes: []string{"4.10.1", "4.10.2"},
+		},
+		{
+			name:    "Invalid/Constraint",
+			channel: ReleaseChannel{VersionConstraint: ">=4.10.z"},
+			expErr:  `Could not parse Range ">=4.10.z": Could not parse version "4.10.z" in ">=4.10.z": Invalid character(s) found in patch number "z"`,
+		},
+		{
+			name: "Invalid/ExcludeVersions",
+			channel: ReleaseChannel{
+				VersionConstraint: ">=4.10.0",
+				ExcludeVersions:   []string{"4.10"},
+			},
+			expErr: "invalid excluded version 4.10: No Major.Minor.Patch elements found",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			versionRange, err := c.channel.VersionRange()
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, v := range c.matches {
+				require.True(t, versionRange(semver.MustParse(v)), v)
+			}
+			for _, v := range c.excludes {
+				require.False(t, versionRange(semver.MustParse(v)), v)
+			}
+		})
+	}
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/blang/semver/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -39,6 +41,32 @@ type Mirror struct {
 type OCP struct {
 	Graph    bool             `json:"graph,omitempty"`
 	Channels []ReleaseChannel `json:"channels,omitempty"`
+	// EUSUpgrade plans the upgrade path between the minimum and maximum
+	// channels as an EUS-to-EUS upgrade, which traverses the minor
+	// versions in between only as intermediate hops.
+	EUSUpgrade *EUSUpgrade `json:"eusUpgrade,omitempty"`
+	// Releases are release payload images, referenced by tag or digest,
+	// mirrored without querying Cincinnati, such as CI and hotfix payloads
+	// that no release channel contains.
+	Releases []ReleaseImage `json:"releases,omitempty"`
+}
+
+// EUSUpgrade configures EUS-to-EUS upgrade path planning.
+type EUSUpgrade struct {
+	// IntermediateChannelPrefix is the prefix of the channels (stable,
+	// fast, or eus) the releases of intermediate minor versions are
+	// taken from. The default is eus, which uses only the releases of
+	// intermediate minor versions in the target EUS channel.
+	IntermediateChannelPrefix string `json:"intermediateChannelPrefix,omitempty"`
+}
+
+// IntermediatePrefix returns the prefix of the
+// channels of intermediate minor versions.
+func (e EUSUpgrade) IntermediatePrefix() string {
+	if e.IntermediateChannelPrefix == "" {
+		return "eus"
+	}
+	return e.IntermediateChannelPrefix
 }
 
 type ReleaseChannel struct {
@@ -50,17 +78,98 @@ type ReleaseChannel struct {
 	// release channel to mirror
 	MaxVersion string `json:"maxVersion"`
 	// HeadsOnly mode mirrors only the channel head.
-	// The default is true.
+	// The default is true unless KeepLatest or
+	// VersionConstraint is set.
 	HeadsOnly *bool `json:"headsOnly,omitempty"`
+	// KeepLatest is the number of latest releases of each
+	// minor version in the release channel to mirror.
+	// Releases that fall out of this window are recorded
+	// in the metadata as eligible for pruning.
+	KeepLatest int `json:"keepLatest,omitempty"`
+	// ConditionalUpdates sets which conditional update edges
+	// in the upgrade graph are followed when planning releases.
+	// The default is ExcludeRisky.
+	ConditionalUpdates ConditionalUpdatePolicy `json:"conditionalUpdates,omitempty"`
+	// AcceptedRisks are the names of the conditional update
+	// risks accepted by the AcceptListed policy.
+	AcceptedRisks []string `json:"acceptedRisks,omitempty"`
+	// PathStrategy determines which releases between the
+	// starting versions and MaxVersion are mirrored.
+	PathStrategy PathStrategy `json:"pathStrategy,omitempty"`
+	// StartingVersions are the versions clusters upgrade from
+	// in addition to MinVersion when PathStrategy is shortest.
+	StartingVersions []string `json:"startingVersions,omitempty"`
+	// VersionConstraint is a semver constraint expression, such as
+	// ">=4.10.20 <4.11, !=4.10.33", selecting the releases in the
+	// release channel to mirror instead of MinVersion and MaxVersion.
+	VersionConstraint string `json:"versionConstraint,omitempty"`
+	// ExcludeVersions are versions matched by
+	// VersionConstraint that are not mirrored.
+	ExcludeVersions []string `json:"excludeVersions,omitempty"`
 }
 
+// PathStrategy determines which releases in
+// a release channel version range are mirrored.
+type PathStrategy string
+
+const (
+	// PathStrategyShortest mirrors only the releases on the shortest
+	// upgrade path from each starting version to the maximum version.
+	PathStrategyShortest PathStrategy = "shortest"
+	// PathStrategyAll mirrors every release between
+	// the minimum and maximum versions.
+	PathStrategyAll PathStrategy = "all"
+)
+
+// ConditionalUpdatePolicy determines which conditional update
+// edges in the upgrade graph are followed.
+type ConditionalUpdatePolicy string
+
+const (
+	// ConditionalUpdatesExcludeRisky follows no conditional update edges.
+	ConditionalUpdatesExcludeRisky ConditionalUpdatePolicy = "ExcludeRisky"
+	// ConditionalUpdatesIncludeAll follows all conditional update edges.
+	ConditionalUpdatesIncludeAll ConditionalUpdatePolicy = "IncludeAll"
+	// ConditionalUpdatesAcceptListed follows conditional update edges
+	// only if all of their risks are listed in AcceptedRisks.
+	ConditionalUpdatesAcceptListed ConditionalUpdatePolicy = "AcceptListed"
+)
+
 func (r ReleaseChannel) IsHeadsOnly() bool {
 	if r.HeadsOnly == nil {
-		return true
+		return r.KeepLatest == 0 && r.VersionConstraint == "" && r.PathStrategy == ""
 	}
 	return *r.HeadsOnly
 }
 
+// VersionRange returns the range of versions matched by
+// VersionConstraint, less the versions in ExcludeVersions.
+// Comparators in the constraint are separated by spaces or commas
+// and alternatives by "||". Versions without a patch number, such
+// as "4.11", match every patch version of the minor version.
+func (r ReleaseChannel) VersionRange() (semver.Range, error) {
+	fields := strings.Fields(strings.ReplaceAll(r.VersionConstraint, ",", " "))
+	for i, field := range fields {
+		if strings.Count(strings.TrimLeft(field, "<>=!"), ".") == 1 {
+			fields[i] = field + ".x"
+		}
+	}
+	versionRange, err := semver.ParseRange(strings.Join(fields, " "))
+	if err != nil {
+		return nil, err
+	}
+	for _, exclude := range r.ExcludeVersions {
+		excluded, err := semver.Parse(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded version %s: %v", exclude, err)
+		}
+		versionRange = versionRange.AND(func(v semver.Version) bool {
+			return !v.EQ(excluded)
+		})
+	}
+	return versionRange, nil
+}
+
 // Operator configures operator catalog mirroring.
 type Operator struct {
 	// Mirror specific operator packages, channels, and versions, and their dependencies.
@@ -128,6 +237,10 @@ type AdditionalImages struct {
 	Image `json:",inline"`
 }
 
+type ReleaseImage struct {
+	Image `json:",inline"`
+}
+
 type BlockedImages struct {
 	Image `json:",inline"`
 }
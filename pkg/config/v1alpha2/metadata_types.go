@@ -31,6 +31,10 @@ type MetadataSpec struct {
 	// PastBlobs is a slice containing information for
 	// all files created for an imageset
 	PastBlobs Blobs `json:"pastBlobs"`
+	// MirroredImages contains every image published to the
+	// mirror registry across all runs and is used to generate
+	// cumulative image mirror policies.
+	MirroredImages []MirroredImage `json:"mirroredImages,omitempty"`
 }
 
 type PastMirror struct {
@@ -41,6 +45,26 @@ type PastMirror struct {
 	Mirror    Mirror     `json:"mirror"`
 	// Operators are metadata about the set of mirrored operators in a mirror operation.
 	Operators []OperatorMetadata `json:"operators,omitempty"`
+	// Releases are the releases planned from release
+	// channels and the releases configured by pullspec.
+	Releases []ReleaseMetadata `json:"releases,omitempty"`
+	// AddedReleases are the resolved releases
+	// that were not resolved by the last run.
+	AddedReleases []ReleaseMetadata `json:"addedReleases,omitempty"`
+	// RemovedReleases are the releases resolved by
+	// the last run that are no longer resolved.
+	RemovedReleases []ReleaseMetadata `json:"removedReleases,omitempty"`
+	// PrunableReleases are releases recorded by past runs that are no
+	// longer planned, such as releases that fell out of the retention
+	// window of their release channel, and are eligible for pruning.
+	// They are carried forward until they are planned again.
+	PrunableReleases []ReleaseMetadata `json:"prunableReleases,omitempty"`
+	// ConditionalReleases are the releases planned on conditional update
+	// edges of the upgrade graph and the risks of those edges.
+	ConditionalReleases []ConditionalRelease `json:"conditionalReleases,omitempty"`
+	// UpgradePaths are the upgrade paths planned for release
+	// channels with the shortest path strategy.
+	UpgradePaths []UpgradePath `json:"upgradePaths,omitempty"`
 }
 
 type Blob struct {
@@ -67,6 +91,16 @@ type Manifest struct {
 	NamespaceName string `json:"namespaceName"`
 }
 
+// MirroredImage is a source image and its location in the mirror registry.
+type MirroredImage struct {
+	// Source is the image reference in the source registry.
+	Source string `json:"source"`
+	// Destination is the image reference in the mirror registry.
+	Destination string `json:"destination"`
+	// Type is the category of the image (e.g. ocpRelease, generic).
+	Type string `json:"type"`
+}
+
 // OperatorMetadata holds an Operator's post-mirror metadata.
 type OperatorMetadata struct {
 	// Catalog references a catalog name from the mirror spec.
@@ -77,6 +111,49 @@ type OperatorMetadata struct {
 	ImagePin string `json:"imagePin"`
 }
 
+// ReleaseMetadata holds a release's post-mirror metadata.
+type ReleaseMetadata struct {
+	// Channel is the release channel the release was mirrored from.
+	// It is empty for releases configured by pullspec.
+	Channel string `json:"channel"`
+	// Version is the release version.
+	Version string `json:"version"`
+	// Image is the release payload image.
+	Image string `json:"image"`
+}
+
+// UpgradePath is an upgrade path in a release channel.
+type UpgradePath struct {
+	// Channel is the release channel of the path.
+	Channel string `json:"channel"`
+	// Versions are the versions on the path, from
+	// the starting version to the target version.
+	Versions []string `json:"versions"`
+}
+
+// ConditionalRelease is a release reached by a
+// conditional update edge in the upgrade graph.
+type ConditionalRelease struct {
+	// Version is the release version.
+	Version string `json:"version"`
+	// Image is the release payload image.
+	Image string `json:"image"`
+	// Risks of the conditional update edge.
+	Risks []UpdateRisk `json:"risks"`
+}
+
+// UpdateRisk is a risk of a conditional update edge.
+type UpdateRisk struct {
+	// Name is the CamelCase reason for the risk.
+	Name string `json:"name"`
+	// URL links to documentation about the risk.
+	URL string `json:"url"`
+	// Message is a human-readable description of the risk.
+	Message string `json:"message,omitempty"`
+	// PromQL are the queries matching clusters exposed to the risk.
+	PromQL []string `json:"promql,omitempty"`
+}
+
 var _ io.Writer = &InlinedIndex{}
 
 type InlinedIndex json.RawMessage
@@ -2,14 +2,16 @@ package config
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/openshift/library-go/pkg/image/reference"
 	"github.com/openshift/oc-mirror/pkg/config/v1alpha2"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 type validationFunc func(cfg *v1alpha2.ImageSetConfiguration) error
 
-var validationChecks = []validationFunc{validateOperatorOptions}
+var validationChecks = []validationFunc{validateOperatorOptions, validateReleaseChannels, validateReleaseImages}
 
 func Validate(cfg *v1alpha2.ImageSetConfiguration) error {
 	var errs []error
@@ -31,3 +33,106 @@ func validateOperatorOptions(cfg *v1alpha2.ImageSetConfiguration) error {
 	}
 	return nil
 }
+
+func validateReleaseChannels(cfg *v1alpha2.ImageSetConfiguration) error {
+	if eus := cfg.Mirror.OCP.EUSUpgrade; eus != nil {
+		switch eus.IntermediatePrefix() {
+		case "stable", "fast", "eus":
+		default:
+			return fmt.Errorf(
+				"invalid configuration option: eusUpgrade intermediateChannelPrefix must be one of stable, fast, or eus, got %q",
+				eus.IntermediateChannelPrefix,
+			)
+		}
+	}
+	for _, ch := range cfg.Mirror.OCP.Channels {
+		if ch.KeepLatest < 0 {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define a negative keepLatest", ch.Name,
+			)
+		}
+		switch ch.ConditionalUpdates {
+		case "", v1alpha2.ConditionalUpdatesExcludeRisky, v1alpha2.ConditionalUpdatesIncludeAll:
+			if len(ch.AcceptedRisks) != 0 {
+				return fmt.Errorf(
+					"invalid configuration option: release channel %s can only define acceptedRisks with conditionalUpdates set to %s",
+					ch.Name, v1alpha2.ConditionalUpdatesAcceptListed,
+				)
+			}
+		case v1alpha2.ConditionalUpdatesAcceptListed:
+		default:
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s has unknown conditionalUpdates policy %q", ch.Name, ch.ConditionalUpdates,
+			)
+		}
+		switch ch.PathStrategy {
+		case "", v1alpha2.PathStrategyAll:
+			if len(ch.StartingVersions) != 0 {
+				return fmt.Errorf(
+					"invalid configuration option: release channel %s can only define startingVersions with pathStrategy set to %s",
+					ch.Name, v1alpha2.PathStrategyShortest,
+				)
+			}
+		case v1alpha2.PathStrategyShortest:
+		default:
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s has unknown pathStrategy %q", ch.Name, ch.PathStrategy,
+			)
+		}
+		if ch.KeepLatest != 0 && ch.PathStrategy != "" {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define both keepLatest and pathStrategy", ch.Name,
+			)
+		}
+		if ch.PathStrategy != "" && ch.IsHeadsOnly() {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define pathStrategy with headsOnly set to true", ch.Name,
+			)
+		}
+		if ch.KeepLatest != 0 && ch.IsHeadsOnly() {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define keepLatest with headsOnly set to true", ch.Name,
+			)
+		}
+		if ch.VersionConstraint == "" {
+			if len(ch.ExcludeVersions) != 0 {
+				return fmt.Errorf(
+					"invalid configuration option: release channel %s can only define excludeVersions with versionConstraint", ch.Name,
+				)
+			}
+			continue
+		}
+		if _, err := ch.VersionRange(); err != nil {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s has invalid versionConstraint %q: %v", ch.Name, ch.VersionConstraint, err,
+			)
+		}
+		if ch.MinVersion != "" || ch.MaxVersion != "" {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define versionConstraint with minVersion or maxVersion", ch.Name,
+			)
+		}
+		if ch.PathStrategy != "" {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define both versionConstraint and pathStrategy", ch.Name,
+			)
+		}
+		if ch.IsHeadsOnly() {
+			return fmt.Errorf(
+				"invalid configuration option: release channel %s cannot define versionConstraint with headsOnly set to true", ch.Name,
+			)
+		}
+	}
+	return nil
+}
+
+func validateReleaseImages(cfg *v1alpha2.ImageSetConfiguration) error {
+	for _, rel := range cfg.Mirror.OCP.Releases {
+		if _, err := reference.Parse(rel.Name); err != nil || rel.Name == "" {
+			return fmt.Errorf(
+				"invalid configuration option: release %q is not a valid image reference", rel.Name,
+			)
+		}
+	}
+	return nil
+}
@@ -87,6 +87,351 @@ func TestValidate(t *testing.T) {
 			},
 			expError: "invalid configuration option: catalog cannot define packages with headsOnly set to true",
 		},
+		{
+			name: "Valid/KeepLatest",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", KeepLatest: 3},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/KeepLatestHeadsOnly",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", KeepLatest: 3, HeadsOnly: &trueValue},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 cannot define keepLatest with headsOnly set to true",
+		},
+		{
+			name: "Invalid/NegativeKeepLatest",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", KeepLatest: -1},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 cannot define a negative keepLatest",
+		},
+		{
+			name: "Valid/AcceptListedRisks",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{
+									Name:               "stable-4.9",
+									ConditionalUpdates: v1alpha2.ConditionalUpdatesAcceptListed,
+									AcceptedRisks:      []string{"AlibabaStorageDriverDemo"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/AcceptedRisksWithoutAcceptListed",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{
+									Name:               "stable-4.9",
+									ConditionalUpdates: v1alpha2.ConditionalUpdatesIncludeAll,
+									AcceptedRisks:      []string{"AlibabaStorageDriverDemo"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 can only define acceptedRisks with conditionalUpdates set to AcceptListed",
+		},
+		{
+			name: "Invalid/UnknownConditionalUpdates",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", ConditionalUpdates: "Sometimes"},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: release channel stable-4.9 has unknown conditionalUpdates policy "Sometimes"`,
+		},
+		{
+			name: "Valid/PathStrategyShortest",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{
+									Name:             "stable-4.9",
+									PathStrategy:     v1alpha2.PathStrategyShortest,
+									StartingVersions: []string{"4.9.5"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/StartingVersionsPathStrategyAll",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{
+									Name:             "stable-4.9",
+									PathStrategy:     v1alpha2.PathStrategyAll,
+									StartingVersions: []string{"4.9.5"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 can only define startingVersions with pathStrategy set to shortest",
+		},
+		{
+			name: "Invalid/UnknownPathStrategy",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", PathStrategy: "longest"},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: release channel stable-4.9 has unknown pathStrategy "longest"`,
+		},
+		{
+			name: "Invalid/PathStrategyHeadsOnly",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", PathStrategy: v1alpha2.PathStrategyAll, HeadsOnly: &trueValue},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 cannot define pathStrategy with headsOnly set to true",
+		},
+		{
+			name: "Invalid/KeepLatestPathStrategy",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.9", KeepLatest: 2, PathStrategy: v1alpha2.PathStrategyAll},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.9 cannot define both keepLatest and pathStrategy",
+		},
+		{
+			name: "Valid/VersionConstraint",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", VersionConstraint: ">=4.10.20 <4.11, !=4.10.33", ExcludeVersions: []string{"4.10.25"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/VersionConstraint",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", VersionConstraint: ">=4.10.z"},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: release channel stable-4.10 has invalid versionConstraint ">=4.10.z": Could not parse Range ">=4.10.z": Could not parse version "4.10.z" in ">=4.10.z": Invalid character(s) found in patch number "z"`,
+		},
+		{
+			name: "Invalid/ExcludeVersionsNoConstraint",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", ExcludeVersions: []string{"4.10.25"}},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.10 can only define excludeVersions with versionConstraint",
+		},
+		{
+			name: "Invalid/VersionConstraintMinVersion",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", MinVersion: "4.10.0", VersionConstraint: ">=4.10.20"},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.10 cannot define versionConstraint with minVersion or maxVersion",
+		},
+		{
+			name: "Invalid/VersionConstraintPathStrategy",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", VersionConstraint: ">=4.10.20", PathStrategy: v1alpha2.PathStrategyAll},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.10 cannot define both versionConstraint and pathStrategy",
+		},
+		{
+			name: "Invalid/VersionConstraintHeadsOnly",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "stable-4.10", VersionConstraint: ">=4.10.20", HeadsOnly: &trueValue},
+							},
+						},
+					},
+				},
+			},
+			expError: "invalid configuration option: release channel stable-4.10 cannot define versionConstraint with headsOnly set to true",
+		},
+		{
+			name: "Valid/Releases",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Releases: []v1alpha2.ReleaseImage{
+								{Image: v1alpha2.Image{Name: "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-06-01-000000"}},
+								{Image: v1alpha2.Image{Name: "quay.io/openshift-release-dev/ocp-release@sha256:d0b7f34c53a45a1bdc3e8ef4f3f8d17ebc1b4b8c27cb4a8e6d5c84c3b4a0a001"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/ReleaseEmpty",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Releases: []v1alpha2.ReleaseImage{
+								{Image: v1alpha2.Image{Name: ""}},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: release "" is not a valid image reference`,
+		},
+		{
+			name: "Invalid/ReleaseReference",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Releases: []v1alpha2.ReleaseImage{
+								{Image: v1alpha2.Image{Name: "quay.io/openshift-release-dev/OCP-release:4.11"}},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: release "quay.io/openshift-release-dev/OCP-release:4.11" is not a valid image reference`,
+		},
+		{
+			name: "Valid/EUSUpgrade",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "eus-4.10"},
+								{Name: "eus-4.12"},
+							},
+							EUSUpgrade: &v1alpha2.EUSUpgrade{IntermediateChannelPrefix: "stable"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/EUSUpgradePrefix",
+			config: &v1alpha2.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v1alpha2.ImageSetConfigurationSpec{
+					Mirror: v1alpha2.Mirror{
+						OCP: v1alpha2.OCP{
+							Channels: []v1alpha2.ReleaseChannel{
+								{Name: "eus-4.10"},
+								{Name: "eus-4.12"},
+							},
+							EUSUpgrade: &v1alpha2.EUSUpgrade{IntermediateChannelPrefix: "candidate"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration option: eusUpgrade intermediateChannelPrefix must be one of stable, fast, or eus, got "candidate"`,
+		},
 	}
 
 	for _, c := range cases {
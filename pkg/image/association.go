@@ -1,7 +1,9 @@
 package image
 
 import (
+	"bufio"
 	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +11,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
 
 	ctrsimgmanifest "github.com/containers/image/v5/manifest"
 	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -152,23 +157,56 @@ func (as AssociationSet) Merge(in AssociationSet) {
 	}
 }
 
-// Encode Associations in an efficient, opaque format.
+const (
+	// AssociationsAPIVersion is the current version of the
+	// serialized image associations format.
+	AssociationsAPIVersion = "mirror.openshift.io/v1alpha2"
+	// AssociationsKind is the kind of the serialized image associations.
+	AssociationsKind = "ImageAssociations"
+)
+
+// associationsVersionRe matches the major version of an API version.
+var associationsVersionRe = regexp.MustCompile(`^(v[0-9]+)((alpha|beta)[0-9]+)?$`)
+
+// associationsFile is the versioned, serialized form of an AssociationSet.
+type associationsFile struct {
+	APIVersion   string         `json:"apiVersion"`
+	Kind         string         `json:"kind"`
+	Associations AssociationSet `json:"associations"`
+}
+
+// Encode Associations as versioned JSON.
 func (as AssociationSet) Encode(w io.Writer) error {
 	if err := as.validate(); err != nil {
 		return fmt.Errorf("invalid image associations: %v", err)
 	}
-	enc := gob.NewEncoder(w)
-	if err := enc.Encode(as); err != nil {
+	file := associationsFile{
+		APIVersion:   AssociationsAPIVersion,
+		Kind:         AssociationsKind,
+		Associations: as,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
 		return fmt.Errorf("error encoding image associations: %v", err)
 	}
 	return nil
 }
 
-// Decode Associations from an opaque format. Only useable if Associations
-// was encoded with Encode().
+// Decode Associations encoded with Encode(). The legacy gob format
+// written by older versions is detected and decoded as well.
 func (as *AssociationSet) Decode(r io.Reader) error {
-	dec := gob.NewDecoder(r)
-	if err := dec.Decode(as); err != nil {
+	br := bufio.NewReader(r)
+	isJSON, err := isJSONStream(br)
+	if err != nil {
+		return fmt.Errorf("error decoding image associations: %v", err)
+	}
+	if isJSON {
+		err = as.decodeJSON(br)
+	} else {
+		err = gob.NewDecoder(br).Decode(as)
+	}
+	if err != nil {
 		return fmt.Errorf("error decoding image associations: %v", err)
 	}
 	// Update paths for local usage.
@@ -184,6 +222,60 @@ func (as *AssociationSet) Decode(r io.Reader) error {
 	return nil
 }
 
+func (as *AssociationSet) decodeJSON(r io.Reader) error {
+	// Unknown fields are ignored so associations written by newer
+	// versions with additive changes can still be read.
+	var file associationsFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return err
+	}
+	if !supportedAssociationsVersion(file.APIVersion) || file.Kind != AssociationsKind {
+		return fmt.Errorf("unsupported image associations %s, %s", file.APIVersion, file.Kind)
+	}
+	*as = file.Associations
+	if *as == nil {
+		*as = AssociationSet{}
+	}
+	return nil
+}
+
+// supportedAssociationsVersion returns true if apiVersion has the same
+// group and major version as AssociationsAPIVersion. Versions only differing
+// in their alpha or beta level are compatible.
+func supportedAssociationsVersion(apiVersion string) bool {
+	major := func(apiVersion string) (string, string, bool) {
+		idx := strings.LastIndex(apiVersion, "/")
+		if idx == -1 {
+			return "", "", false
+		}
+		match := associationsVersionRe.FindStringSubmatch(apiVersion[idx+1:])
+		if match == nil {
+			return "", "", false
+		}
+		return apiVersion[:idx], match[1], true
+	}
+	group, version, ok := major(apiVersion)
+	if !ok {
+		return false
+	}
+	currentGroup, currentVersion, _ := major(AssociationsAPIVersion)
+	return group == currentGroup && version == currentVersion
+}
+
+// isJSONStream reports whether the first non-whitespace
+// character in br starts a JSON object.
+func isJSONStream(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b == '{', br.UnreadByte()
+		}
+	}
+}
+
 func (as AssociationSet) validate() error {
 	var errs []error
 	for _, imageName := range as.Keys() {
@@ -1,6 +1,10 @@
 package image
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"os"
@@ -363,6 +367,96 @@ func TestAdd(t *testing.T) {
 	require.Equal(t, newAssoc, assoc)
 }
 
+func TestEncodeDecode(t *testing.T) {
+	type spec struct {
+		name     string
+		encode   func(io.Writer, AssociationSet) error
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/JSON",
+			encode: func(w io.Writer, as AssociationSet) error {
+				return as.Encode(w)
+			},
+		},
+		{
+			name: "Valid/LegacyGob",
+			encode: func(w io.Writer, as AssociationSet) error {
+				return gob.NewEncoder(w).Encode(as)
+			},
+		},
+		{
+			name: "Valid/NewerMinorVersionUnknownFields",
+			encode: func(w io.Writer, as AssociationSet) error {
+				var buf bytes.Buffer
+				if err := as.Encode(&buf); err != nil {
+					return err
+				}
+				var file map[string]interface{}
+				if err := json.Unmarshal(buf.Bytes(), &file); err != nil {
+					return err
+				}
+				file["apiVersion"] = "mirror.openshift.io/v1beta1"
+				file["status"] = "additive"
+				for _, assocs := range file["associations"].(map[string]interface{}) {
+					for _, assoc := range assocs.(map[string]interface{}) {
+						assoc.(map[string]interface{})["annotations"] = map[string]string{"foo": "bar"}
+					}
+				}
+				return json.NewEncoder(w).Encode(file)
+			},
+		},
+		{
+			name: "Invalid/UnsupportedGroup",
+			encode: func(w io.Writer, as AssociationSet) error {
+				_, err := io.WriteString(w, `{"apiVersion": "example.com/v1alpha2", "kind": "ImageAssociations", "associations": {}}`)
+				return err
+			},
+			expError: "error decoding image associations: unsupported image associations example.com/v1alpha2, ImageAssociations",
+		},
+		{
+			name: "Invalid/UnsupportedVersion",
+			encode: func(w io.Writer, as AssociationSet) error {
+				_, err := io.WriteString(w, `{"apiVersion": "mirror.openshift.io/v9", "kind": "ImageAssociations", "associations": {}}`)
+				return err
+			},
+			expError: "error decoding image associations: unsupported image associations mirror.openshift.io/v9, ImageAssociations",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			asSet := makeEncodableAssociationSet()
+			var buf bytes.Buffer
+			require.NoError(t, c.encode(&buf, asSet))
+
+			var decoded AssociationSet
+			err := decoded.Decode(&buf)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, asSet, decoded)
+		})
+	}
+}
+
+func TestEncodeFormat(t *testing.T) {
+	asSet := makeEncodableAssociationSet()
+	var buf bytes.Buffer
+	require.NoError(t, asSet.Encode(&buf))
+
+	var file map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &file))
+	require.Equal(t, AssociationsAPIVersion, file["apiVersion"])
+	require.Equal(t, AssociationsKind, file["kind"])
+	assoc := file["associations"].(map[string]interface{})[setTestKeyName].(map[string]interface{})[testKeyName]
+	require.Equal(t, "generic", assoc.(map[string]interface{})["type"])
+}
+
 func makeTestAssocationSet() AssociationSet {
 	asSet := AssociationSet{}
 	assocs := Associations{}
@@ -377,3 +471,11 @@ func makeTestAssocationSet() AssociationSet {
 	asSet[setTestKeyName] = assocs
 	return asSet
 }
+
+func makeEncodableAssociationSet() AssociationSet {
+	asSet := makeTestAssocationSet()
+	assoc := asSet[setTestKeyName][testKeyName]
+	assoc.LayerDigests = []string{"sha256:layer"}
+	asSet[setTestKeyName][testKeyName] = assoc
+	return asSet
+}
@@ -1,5 +1,10 @@
 package image
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type ImageType int
 
 const (
@@ -22,3 +27,37 @@ var imageTypeStrings = map[ImageType]string{
 func (it ImageType) String() string {
 	return imageTypeStrings[it]
 }
+
+// MarshalJSON encodes the ImageType by name so serialized
+// data stays readable and stable if the enum is reordered.
+func (it ImageType) MarshalJSON() ([]byte, error) {
+	s, ok := imageTypeStrings[it]
+	if !ok {
+		return nil, fmt.Errorf("unknown image type %d", it)
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes an ImageType from its name.
+func (it *ImageType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	typ, err := ParseImageType(s)
+	if err != nil {
+		return err
+	}
+	*it = typ
+	return nil
+}
+
+// ParseImageType returns the ImageType with name s.
+func ParseImageType(s string) (ImageType, error) {
+	for typ, name := range imageTypeStrings {
+		if name == s {
+			return typ, nil
+		}
+	}
+	return TypeInvalid, fmt.Errorf("unknown image type %q", s)
+}